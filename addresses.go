@@ -0,0 +1,218 @@
+package godump
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// WithAddresses annotates pointers, maps, slices and interfaces with the
+// address they refer to, and slices with their length and capacity. A value
+// whose memory overlaps one printed earlier, such as two slices sharing a
+// backing array, is marked with "aliases &N" pointing at the earlier value's
+// anchor. Addresses change between runs, so leave this disabled for golden
+// or snapshot tests, or turn it back off with [WithoutAddresses].
+// @group Options
+//
+// Example: show addresses and aliasing
+//
+//	// Default: disabled
+//	base := make([]int, 3, 4)
+//	view := base[1:]
+//	d := godump.NewDumper(godump.WithAddresses())
+//	d.Dump(base, view)
+//	// &1 #[]int @0xc000018180 len=3 cap=4 [
+//	//   0 => 0 #int
+//	//   1 => 0 #int
+//	//   2 => 0 #int
+//	// ]
+//	// #[]int @0xc000018188 len=2 cap=3 aliases &1 [
+//	//   0 => 0 #int
+//	//   1 => 0 #int
+//	// ]
+func WithAddresses() Option {
+	return func(d *Dumper) *Dumper {
+		d.showAddresses = true
+		return d
+	}
+}
+
+// WithoutAddresses turns off address annotations enabled by [WithAddresses],
+// for example to keep test output deterministic with a shared set of options.
+// @group Options
+//
+// Example: hide addresses
+//
+//	// Default: addresses hidden
+//	opts := []godump.Option{godump.WithAddresses()}
+//	d := godump.NewDumper(append(opts, godump.WithoutAddresses())...)
+//	d.Dump(&struct{}{})
+//	// #*struct {} {
+//	// }
+func WithoutAddresses() Option {
+	return func(d *Dumper) *Dumper {
+		d.showAddresses = false
+		return d
+	}
+}
+
+// inspectAddresses records on n the addresses of v shown by WithAddresses,
+// following pointers to the map or slice they lead to.
+func (d *Dumper) inspectAddresses(n *Node, v reflect.Value) {
+	if !d.showAddresses {
+		return
+	}
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		n.Addrs = append(n.Addrs, v.Pointer())
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		if !v.IsNil() {
+			n.Addrs = append(n.Addrs, v.Pointer())
+		}
+	case reflect.Interface:
+		if addr, ok := boxedAddress(v); ok {
+			n.Addrs = append(n.Addrs, addr)
+		}
+	}
+}
+
+// addressTag returns the " @0x..." annotation for n, with the length and
+// capacity of slices, or "" when addresses are disabled.
+func (d *Dumper) addressTag(n *Node) string {
+	if !d.showAddresses {
+		return ""
+	}
+	// Byte slices show their length and capacity in the hex dump header.
+	extra := ""
+	if n.slice {
+		extra = fmt.Sprintf(" len=%d cap=%d", n.Len, n.Cap)
+	}
+	if len(n.Addrs) == 0 && extra == "" {
+		return ""
+	}
+
+	tag := ""
+	if len(n.Addrs) > 0 {
+		addrs := make([]string, len(n.Addrs))
+		for i, addr := range n.Addrs {
+			addrs[i] = fmt.Sprintf("%#x", addr)
+		}
+		tag = " @" + strings.Join(addrs, " → ")
+	}
+	tag = d.colorize(RoleRef, tag+extra)
+	if n.Alias > 0 {
+		tag += d.colorize(RoleWarn, fmt.Sprintf(" aliases &%d", n.Alias))
+	}
+	return tag
+}
+
+// boxedAddress returns the address of the value boxed in a non-nil interface.
+// Dynamic values stored in the interface itself report false.
+func boxedAddress(v reflect.Value) (uintptr, bool) {
+	if v.IsNil() {
+		return 0, false
+	}
+	if pointerShaped(v.Elem().Type()) {
+		return 0, false
+	}
+	// Unexported values that are not addressable cannot be read.
+	if v = forceExported(v); !v.CanInterface() {
+		return 0, false
+	}
+	return uintptr(interfaceData(v.Interface())), true
+}
+
+// pointerShaped reports whether values of t are stored directly in an
+// interface's data word rather than boxed, which is the case for pointer-like
+// kinds and for structs and arrays that wrap a single one of them.
+func pointerShaped(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	case reflect.Struct:
+		return t.NumField() == 1 && pointerShaped(t.Field(0).Type)
+	case reflect.Array:
+		return t.Len() == 1 && pointerShaped(t.Elem())
+	default:
+		return false
+	}
+}
+
+// memRegion is the memory a pointer or slice refers to.
+type memRegion struct {
+	start, end uintptr
+	key        refKey
+	// seq is the order in which the value is printed.
+	seq int
+}
+
+// recordRegion notes the memory v refers to so overlaps can be found before
+// printing.
+func (d *Dumper) recordRegion(v reflect.Value, key refKey, state *dumpState) {
+	if !d.showAddresses {
+		return
+	}
+	var size uintptr
+	switch v.Kind() {
+	case reflect.Ptr:
+		size = v.Type().Elem().Size()
+	case reflect.Slice:
+		size = v.Type().Elem().Size() * uintptr(v.Cap())
+	}
+	if size == 0 {
+		return
+	}
+	start := v.Pointer()
+	state.regions = append(state.regions, memRegion{start: start, end: start + size, key: key, seq: len(state.regions)})
+}
+
+// linkAliases finds every recorded region that overlaps one printed before it
+// and links it to the earliest such region, which then gets an anchor.
+func linkAliases(state *dumpState) {
+	if len(state.regions) < 2 {
+		return
+	}
+	regions := append([]memRegion(nil), state.regions...)
+	sort.Slice(regions, func(i, j int) bool { return regions[i].start < regions[j].start })
+
+	earliest := map[refKey]memRegion{}
+	var active []memRegion
+	for _, r := range regions {
+		kept := active[:0]
+		for _, a := range active {
+			if a.end > r.start {
+				kept = append(kept, a)
+			}
+		}
+		active = kept
+
+		for _, a := range active {
+			first, later := a, r
+			if r.seq < a.seq {
+				first, later = r, a
+			}
+			if prev, ok := earliest[later.key]; !ok || first.seq < prev.seq {
+				earliest[later.key] = first
+			}
+		}
+		active = append(active, r)
+	}
+
+	if len(earliest) > 0 {
+		state.aliases = make(map[refKey]refKey, len(earliest))
+	}
+	for key, first := range earliest {
+		state.aliases[key] = first.key
+		state.shared[first.key] = true
+	}
+}
+
+// interfaceData returns the data word of i: the boxed value's address, or the
+// value itself when it is pointer-shaped.
+func interfaceData(i any) unsafe.Pointer {
+	return (*[2]unsafe.Pointer)(unsafe.Pointer(&i))[1]
+}
@@ -0,0 +1,107 @@
+package godump
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+func TestAddressesHiddenByDefault(t *testing.T) {
+	n := 1
+	out := newDumperT(t, WithoutHeader()).DumpStr(&n, []int{1}, map[string]int{"a": 1})
+	assert.NotContains(t, out, "@0x")
+
+	out = newDumperT(t, WithoutHeader(), WithAddresses(), WithoutAddresses()).DumpStr(&n)
+	assert.Equal(t, "1 #*int\n", out)
+}
+
+func TestAddressesSliceAliasing(t *testing.T) {
+	base := make([]int, 2, 3)
+	view := base[1:]
+	other := []int{7}
+	d := newDumperT(t, WithoutHeader(), WithAddresses())
+
+	out := d.DumpStr(base, view, other)
+	assert.Equal(t, fmt.Sprintf(`&1 #[]int @%#x len=2 cap=3 [
+  0 => 0 #int
+  1 => 0 #int
+]
+#[]int @%#x len=1 cap=2 aliases &1 [
+  0 => 0 #int
+]
+#[]int @%#x len=1 cap=1 [
+  0 => 7 #int
+]
+`, reflect.ValueOf(base).Pointer(), reflect.ValueOf(view).Pointer(), reflect.ValueOf(other).Pointer()), out)
+
+	// A slice is marked even when the aliased elements lie beyond its length.
+	head := base[:1]
+	out = d.DumpStr(head, view)
+	assert.Contains(t, out, "len=1 cap=2 aliases &1 [")
+}
+
+func TestAddressesPointersMapsAndInterfaces(t *testing.T) {
+	type Holder struct {
+		Items []int
+		Last  *int
+		Any   any
+		Index map[string]int
+		Buf   []byte
+	}
+	items := []int{1, 2}
+	h := &Holder{Items: items, Last: &items[1], Any: 3.5, Index: map[string]int{}, Buf: []byte("hi")}
+	out := newDumperT(t, WithoutHeader(), WithAddresses()).DumpStr(h)
+
+	assert.Contains(t, out, fmt.Sprintf("#*godump.Holder @%p {\n", h))
+	assert.Contains(t, out, fmt.Sprintf("+Items => &1 #[]int @%p len=2 cap=2 [\n", items))
+	assert.Contains(t, out, fmt.Sprintf("+Last  => 2 #*int @%p aliases &1\n", &items[1]))
+	assert.Contains(t, out, fmt.Sprintf("+Any   => 3.5 #float64 @%#x\n", uintptr(interfaceData(h.Any))))
+	assert.Contains(t, out, fmt.Sprintf("+Index => #map[string]int @%#x {\n", reflect.ValueOf(h.Index).Pointer()))
+	assert.Contains(t, out, fmt.Sprintf("+Buf   => ([]uint8) (len=2 cap=2) @%p {\n", h.Buf))
+
+	pp := &h.Last
+	out = newDumperT(t, WithoutHeader(), WithAddresses()).DumpStr(pp)
+	assert.Equal(t, fmt.Sprintf("2 #**int @%p → %p\n", pp, &items[1]), out)
+}
+
+func TestAddressesDisableCompact(t *testing.T) {
+	d := newDumperT(t, WithoutHeader(), WithAddresses(), WithCompact(80))
+	assert.Contains(t, d.DumpStr([]int{1, 2}), "len=2 cap=2 [\n")
+
+	type Point struct{ X, Y int }
+	assert.Equal(t, "#godump.Point {X: 1, Y: 2}\n", d.DumpStr(Point{X: 1, Y: 2}))
+}
+
+func TestLinkAliasesPicksEarliestRegion(t *testing.T) {
+	key := func(n int) refKey { return refKey{ptr: uintptr(n)} }
+	state := newDumpState()
+	state.regions = []memRegion{
+		{start: 100, end: 140, key: key(1), seq: 0},
+		{start: 200, end: 210, key: key(2), seq: 1},
+		{start: 120, end: 130, key: key(3), seq: 2},
+		{start: 90, end: 125, key: key(4), seq: 3},
+		{start: 140, end: 150, key: key(5), seq: 4},
+	}
+	linkAliases(state)
+
+	assert.Equal(t, map[refKey]refKey{key(3): key(1), key(4): key(1)}, state.aliases)
+	assert.True(t, state.shared[key(1)])
+	assert.False(t, state.shared[key(2)])
+}
+
+func TestAddressesUnexportedInterfaceInMapValue(t *testing.T) {
+	type box struct {
+		x any
+	}
+	m := map[string]box{"a": {x: 5}}
+	d := newDumperT(t, WithoutHeader(), WithAddresses())
+
+	// Map values are not addressable, so the boxed value has no address.
+	out := d.DumpStr(m)
+	assert.Contains(t, out, "-x => 5 #int\n")
+	assert.Equal(t, NodeMap, d.Inspect(m).Kind)
+	assert.Contains(t, d.DumpHTML(m), "5")
+	assert.Contains(t, d.DiffStr(m, map[string]box{"a": {x: 6}}), "6")
+}
@@ -0,0 +1,249 @@
+package godump
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WithMaxNodes stops a dump after n values have been visited, so a wide
+// graph cannot produce unbounded output. Containers cut short show
+// "... (truncated)" and the dump ends with a budget exceeded marker.
+// Zero means no limit, the default.
+// @group Options
+//
+// Example: bound the work of a dump in a hot path
+//
+//	d := godump.NewDumper(godump.WithMaxNodes(3))
+//	d.Dump([]int{1, 2, 3, 4})
+//	// #[]int [
+//	//   0 => 1 #int
+//	//   1 => 2 #int
+//	//   ... (truncated)
+//	// ]
+//	// ... (budget exceeded: 3 nodes, 57 bytes)
+func WithMaxNodes(n int) Option {
+	return func(d *Dumper) *Dumper {
+		if n >= 0 {
+			d.maxNodes = n
+		}
+		return d
+	}
+}
+
+// WithMaxOutputBytes stops a dump once its output would exceed n bytes. Only
+// whole lines are written, followed by a budget exceeded marker. Every value
+// prints at least one byte, so n also caps the values visited as WithMaxNodes
+// does, which bounds JSON output and Inspect too. Zero means no limit, the
+// default.
+// @group Options
+//
+// Example: cap the size of dumps sent to logs
+//
+//	d := godump.NewDumper(godump.WithMaxOutputBytes(64 << 10))
+//	d.Dump(map[string]int{"a": 1})
+//	// #map[string]int {
+//	//   a => 1 #int
+//	// }
+func WithMaxOutputBytes(n int) Option {
+	return func(d *Dumper) *Dumper {
+		if n >= 0 {
+			d.maxOutputBytes = n
+		}
+		return d
+	}
+}
+
+// WithTimeout stops inspecting values once a dump has taken longer than t,
+// printing what was inspected so far and a budget exceeded marker. Zero
+// means no limit, the default.
+// @group Options
+//
+// Example: never spend more than a few milliseconds dumping
+//
+//	d := godump.NewDumper(godump.WithTimeout(5 * time.Millisecond))
+//	d.Dump(map[string]int{"a": 1})
+//	// #map[string]int {
+//	//   a => 1 #int
+//	// }
+func WithTimeout(t time.Duration) Option {
+	return func(d *Dumper) *Dumper {
+		if t >= 0 {
+			d.timeout = t
+		}
+		return d
+	}
+}
+
+// DumpContext prints the values like Dump, stopping early with a budget
+// exceeded marker when ctx is done.
+// @group Dump
+//
+// Example: stop dumping when the request is cancelled
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//	defer cancel()
+//	godump.DumpContext(ctx, map[string]int{"a": 1})
+//	// #map[string]int {
+//	//   a => 1 #int
+//	// }
+func DumpContext(ctx context.Context, vs ...any) {
+	defaultDumper.DumpContext(ctx, vs...)
+}
+
+// DumpContext prints the values like Dump, stopping early with a budget
+// exceeded marker when ctx is done. The dumper's other limits still apply.
+// @group Dump
+//
+// Example: dump within a request's deadline
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//	defer cancel()
+//	d := godump.NewDumper(godump.WithMaxNodes(10000))
+//	d.DumpContext(ctx, map[string]int{"a": 1})
+//	// #map[string]int {
+//	//   a => 1 #int
+//	// }
+func (d *Dumper) DumpContext(ctx context.Context, vs ...any) {
+	d = d.clone()
+	d.ctx = ctx
+	d.Dump(vs...)
+}
+
+// outputBudget bounds the work of one dump. A nil budget has no limits.
+type outputBudget struct {
+	ctx      context.Context
+	deadline time.Time
+	maxNodes int
+	maxBytes int
+
+	// nodes counts the values inspected and counted the values visited by
+	// the reference counting pass.
+	nodes   int
+	counted int
+	// bytes counts the output written.
+	bytes int
+	// exceeded is set once a limit cut the dump short.
+	exceeded bool
+}
+
+// newBudget returns the budget for a dump, or nil when no limit is set.
+func (d *Dumper) newBudget() *outputBudget {
+	if d.maxNodes == 0 && d.maxOutputBytes == 0 && d.timeout == 0 && d.ctx == nil {
+		return nil
+	}
+	b := &outputBudget{ctx: d.ctx, maxNodes: d.maxNodes, maxBytes: d.maxOutputBytes}
+	// Every value prints at least one byte, so a byte limit also bounds the
+	// values worth inspecting.
+	if b.maxBytes > 0 && (b.maxNodes == 0 || b.maxBytes < b.maxNodes) {
+		b.maxNodes = b.maxBytes
+	}
+	if d.timeout > 0 {
+		b.deadline = time.Now().Add(d.timeout)
+	}
+	return b
+}
+
+// expired reports whether the context is done or the deadline has passed.
+func (b *outputBudget) expired() bool {
+	if b.ctx != nil && b.ctx.Err() != nil {
+		return true
+	}
+	return !b.deadline.IsZero() && time.Now().After(b.deadline)
+}
+
+// take records an inspected value.
+func (b *outputBudget) take() {
+	if b != nil {
+		b.nodes++
+	}
+}
+
+// allows reports whether another value may be inspected, and records that
+// the dump was cut short when not.
+func (b *outputBudget) allows() bool {
+	if b == nil {
+		return true
+	}
+	if b.exceeded || b.maxNodes > 0 && b.nodes >= b.maxNodes || b.expired() {
+		b.exceeded = true
+		return false
+	}
+	return true
+}
+
+// cut reports whether a limit has cut the dump short.
+func (b *outputBudget) cut() bool {
+	return b != nil && b.exceeded
+}
+
+// countable reports whether the reference counting pass may visit another
+// value. It stops at the same limits as inspection.
+func (b *outputBudget) countable() bool {
+	if b == nil {
+		return true
+	}
+	b.counted++
+	return (b.maxNodes == 0 || b.counted <= b.maxNodes) && !b.expired()
+}
+
+// marker returns the line that ends a dump cut short.
+func (b *outputBudget) marker() string {
+	return fmt.Sprintf("... (budget exceeded: %d nodes, %d bytes)", b.nodes, b.bytes)
+}
+
+// budgetWriter writes whole lines to w until the byte budget is exhausted,
+// then discards the rest.
+type budgetWriter struct {
+	w       io.Writer
+	b       *outputBudget
+	line    []byte
+	stopped bool
+}
+
+// Write buffers p and writes every completed line that fits the budget.
+func (bw *budgetWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 && !bw.stopped {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			bw.line = append(bw.line, p...)
+			if bw.b.maxBytes > 0 && bw.b.bytes+len(bw.line) > bw.b.maxBytes {
+				bw.stop()
+			}
+			break
+		}
+		bw.line = append(bw.line, p[:i+1]...)
+		p = p[i+1:]
+		if bw.b.maxBytes > 0 && bw.b.bytes+len(bw.line) > bw.b.maxBytes {
+			bw.stop()
+			break
+		}
+		if _, err := bw.w.Write(bw.line); err != nil {
+			return n - len(p), err
+		}
+		bw.b.bytes += len(bw.line)
+		bw.line = bw.line[:0]
+	}
+	return n, nil
+}
+
+// stop drops the pending line and any later output.
+func (bw *budgetWriter) stop() {
+	bw.stopped = true
+	bw.b.exceeded = true
+	bw.line = nil
+}
+
+// Flush writes a pending partial line that fits the budget.
+func (bw *budgetWriter) Flush() error {
+	if len(bw.line) == 0 || bw.stopped {
+		return nil
+	}
+	_, err := bw.w.Write(bw.line)
+	bw.b.bytes += len(bw.line)
+	bw.line = bw.line[:0]
+	return err
+}
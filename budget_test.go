@@ -0,0 +1,126 @@
+package godump
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+// wideGraph returns a value with width^depth leaves.
+func wideGraph(width, depth int) any {
+	if depth == 0 {
+		return "leaf"
+	}
+	items := make([]any, width)
+	for i := range items {
+		items[i] = wideGraph(width, depth-1)
+	}
+	return items
+}
+
+func TestWithMaxNodes(t *testing.T) {
+	d := NewDumper(WithoutColor(), WithoutHeader(), WithMaxNodes(3))
+	assert.Equal(t, "#[]int [\n  0 => 1 #int\n  1 => 2 #int\n  ... (truncated)\n]\n... (budget exceeded: 3 nodes, 57 bytes)\n", d.DumpStr([]int{1, 2, 3, 4}))
+
+	// A dump that fits the budget exactly has no marker.
+	assert.Equal(t, "#[]int [\n  0 => 1 #int\n  1 => 2 #int\n]\n", d.DumpStr([]int{1, 2}))
+
+	type order struct {
+		ID    int
+		Items []string
+		Note  string
+	}
+	out := d.DumpStr(order{ID: 1, Items: []string{"a", "b"}, Note: "n"})
+	assert.Contains(t, out, "  +ID    => 1 #int\n  +Items => #[]string [\n    ... (truncated)\n  ]\n  ... (truncated)\n}\n")
+	assert.True(t, strings.HasSuffix(out, "... (budget exceeded: 3 nodes, 103 bytes)\n"))
+
+	// Budgeted output still parses.
+	dumps, err := Parse(out)
+	assert.NoError(t, err)
+	assert.True(t, dumps[0].Values[0].Truncated)
+
+	// Values dumped together share one budget.
+	out = d.DumpStr([]int{1}, []int{2, 3})
+	assert.Contains(t, out, "#[]int [\n  0 => 1 #int\n]\n#[]int [\n  ... (truncated)\n]\n... (budget exceeded: 3 nodes")
+
+	// Every renderer marks the value during which the budget ran out.
+	assert.JSONEq(t, `{"ID": 1, "Items": ["... (truncated)"], "...": "(truncated)", "$truncated": "budget exceeded"}`, d.DumpJSONStr(order{ID: 1, Items: []string{"a", "b"}}))
+	assert.JSONEq(t, `[[1, 2, "... (truncated)", "... (budget exceeded)"], ["... (truncated)"]]`, d.DumpJSONStr([]int{1, 2, 3}, []int{2}))
+	assert.JSONEq(t, `[{"type": "[]int", "len": 1, "cap": 1, "items": [{"type": "int", "value": 1}]}, {"type": "[]int", "len": 2, "cap": 2, "items": [], "truncated": true, "budgetExceeded": true}]`,
+		NewDumper(WithMaxNodes(3), WithJSONMode(JSONTyped)).DumpJSONStr([]int{1}, []int{2, 3}))
+	assert.JSONEq(t, `[1, 2]`, d.DumpJSONStr([]int{1, 2}))
+
+	n := d.Inspect([]int{1, 2, 3})
+	assert.True(t, n.BudgetExceeded)
+	assert.True(t, n.Truncated)
+	assert.False(t, d.Inspect([]int{1, 2}).BudgetExceeded)
+
+	page := d.DumpHTMLPage([]int{1, 2, 3})
+	assert.Contains(t, page, "... (budget exceeded)")
+	assert.NotContains(t, d.DumpHTMLPage([]int{1, 2}), "budget exceeded")
+}
+
+func TestWithMaxOutputBytes(t *testing.T) {
+	full := NewDumper(WithoutColor(), WithoutHeader()).DumpStr(wideGraph(20, 3))
+	d := NewDumper(WithoutColor(), WithoutHeader(), WithMaxOutputBytes(500))
+	out := d.DumpStr(wideGraph(20, 3))
+
+	body, marker, ok := strings.Cut(out, "... (budget exceeded: ")
+	assert.True(t, ok)
+	assert.True(t, len(body) <= 500)
+	assert.True(t, strings.HasSuffix(body, "\n"))
+	assert.True(t, strings.HasPrefix(full, body))
+	assert.True(t, strings.HasSuffix(marker, " bytes)\n"))
+
+	// Lines are never cut, so colors stay balanced.
+	unsetColorEnv(t)
+	t.Setenv("FORCE_COLOR", "3")
+	colored := NewDumper(WithoutHeader(), WithMaxOutputBytes(300)).DumpStr(map[string]string{"a": "x", "b": "y", "c": "z", "d": "w", "e": "v"})
+	for _, line := range strings.Split(strings.TrimSuffix(colored, "\n"), "\n") {
+		assert.Equal(t, strings.Count(line, string(ansiEscape)+"["), 2*strings.Count(line, colorReset))
+	}
+	assert.Contains(t, colored, "budget exceeded")
+
+	// The byte limit also caps the values visited, which bounds JSON.
+	assert.True(t, NewDumper(WithMaxOutputBytes(500)).Inspect(wideGraph(20, 3)).BudgetExceeded)
+	assert.Contains(t, NewDumper(WithMaxOutputBytes(500)).DumpJSONStr(wideGraph(20, 3)), `"... (budget exceeded)"`)
+
+	// A dump that fits is written unchanged.
+	assert.Equal(t, NewDumper(WithoutColor(), WithoutHeader()).DumpStr(1), NewDumper(WithoutColor(), WithoutHeader(), WithMaxOutputBytes(9)).DumpStr(1))
+}
+
+func TestWithTimeout(t *testing.T) {
+	d := NewDumper(WithoutColor(), WithoutHeader(), WithTimeout(time.Nanosecond))
+	graph := wideGraph(30, 4)
+	start := time.Now()
+	out := d.DumpStr(graph)
+	assert.True(t, time.Since(start) < time.Second)
+	assert.True(t, strings.HasPrefix(out, "#[]interface {} [\n"))
+	assert.Contains(t, out, "... (budget exceeded: ")
+
+	out = NewDumper(WithoutColor(), WithoutHeader(), WithTimeout(time.Minute)).DumpStr([]int{1})
+	assert.NotContains(t, out, "budget exceeded")
+}
+
+func TestDumpContext(t *testing.T) {
+	var buf bytes.Buffer
+	d := NewDumper(WithoutColor(), WithoutHeader(), WithWriter(&buf))
+
+	d.DumpContext(context.Background(), []int{1, 2})
+	assert.Equal(t, "#[]int [\n  0 => 1 #int\n  1 => 2 #int\n]\n", buf.String())
+
+	buf.Reset()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d.DumpContext(ctx, []int{1, 2})
+	assert.Equal(t, "#[]int [\n  ... (truncated)\n]\n... (budget exceeded: 1 nodes, 29 bytes)\n", buf.String())
+
+	// The dumper itself is left without the context.
+	buf.Reset()
+	d.Dump([]int{1})
+	assert.NotContains(t, buf.String(), "budget exceeded")
+}
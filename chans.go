@@ -0,0 +1,234 @@
+package godump
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+// WithChannelBuffers shows the elements waiting in buffered channels, oldest
+// first, along with whether the channel is closed. Elements are copied out of
+// the channel's ring buffer without receiving them, so the channel is left
+// untouched. The copy is taken without the channel lock, so an element being
+// sent or received at the same time may be shown half-written. Only element
+// types without pointers are read, such as numbers and structs of numbers;
+// other channels show "(buffer unavailable)" after their len and cap.
+// @group Options
+//
+// Example: show buffered channel elements
+//
+//	// Default: disabled
+//	ch := make(chan int, 4)
+//	ch <- 1
+//	ch <- 2
+//	d := godump.NewDumper(godump.WithChannelBuffers())
+//	d.Dump(ch)
+//	// chan int(0xc000022120) dir=both elem=int len=2 cap=4 [
+//	//   0 => 1 #int
+//	//   1 => 2 #int
+//	// ]
+func WithChannelBuffers() Option {
+	return func(d *Dumper) *Dumper {
+		d.showChanBuffers = true
+		return d
+	}
+}
+
+// inspectChan builds the node for a non-nil channel with its direction,
+// element type, length and capacity, and its buffered elements when enabled.
+func (d *Dumper) inspectChan(v reflect.Value, depth int, state *dumpState) *Node {
+	t := v.Type()
+	n := &Node{
+		Kind:     NodeChan,
+		Type:     d.typeString(t),
+		Value:    fmt.Sprintf("%#x", v.Pointer()),
+		Note:     chanDirName(t.ChanDir()),
+		Len:      v.Len(),
+		Cap:      v.Cap(),
+		elemType: d.typeString(t.Elem()),
+	}
+	if !d.showChanBuffers {
+		return n
+	}
+
+	snap, ok := readChanBuffer(v)
+	if !ok {
+		n.noBuffer = true
+		return n
+	}
+	n.Closed = snap.closed
+	if len(snap.elems) == 0 {
+		return n
+	}
+	if depth >= d.maxDepth {
+		n.MaxDepth = true
+		return n
+	}
+	for i, elem := range snap.elems {
+		if i >= d.maxItems || !state.budget.allows() {
+			n.Truncated = true
+			break
+		}
+		child := d.inspectValue(elem, depth+1, state)
+		child.Name = strconv.Itoa(i)
+		n.Children = append(n.Children, child)
+	}
+	return n
+}
+
+// printChan renders a channel node.
+func (d *Dumper) printChan(w io.Writer, n *Node, indent int) {
+	fmt.Fprintf(w, "%s(%s)", d.colorize(RoleType, n.Type), d.colorize(RoleNumber, n.Value))
+	fmt.Fprint(w, d.colorize(RoleMeta, fmt.Sprintf(" dir=%s elem=%s len=%d cap=%d", n.Note, n.elemType, n.Len, n.Cap)))
+	if n.Cap > 0 && n.Len == n.Cap {
+		fmt.Fprint(w, d.colorize(RoleWarn, " full"))
+	}
+
+	if n.noBuffer {
+		fmt.Fprint(w, d.colorize(RoleMuted, " (buffer unavailable)"))
+		return
+	}
+	if n.Closed {
+		fmt.Fprint(w, d.colorize(RoleWarn, " closed"))
+	}
+	if n.MaxDepth {
+		fmt.Fprint(w, " "+d.colorize(RoleMuted, "... (max depth)"))
+		return
+	}
+	if len(n.Children) == 0 {
+		return
+	}
+
+	fmt.Fprint(w, " [")
+	fmt.Fprintln(w)
+	for _, elem := range n.Children {
+		indentPrint(w, indent+1, fmt.Sprintf("%s => ", d.colorize(RoleIndex, elem.Name)))
+		d.printNode(w, elem, indent+1)
+		fmt.Fprintln(w)
+	}
+	if n.Truncated {
+		indentPrint(w, indent+1, d.colorize(RoleMuted, "... (truncated)"))
+		fmt.Fprintln(w)
+	}
+	indentPrint(w, indent, "")
+	fmt.Fprint(w, "]")
+}
+
+// chanDirName names a channel direction.
+func chanDirName(dir reflect.ChanDir) string {
+	switch dir {
+	case reflect.RecvDir:
+		return "recv"
+	case reflect.SendDir:
+		return "send"
+	default:
+		return "both"
+	}
+}
+
+// chanSnapshot is a copy of a channel's buffered elements.
+type chanSnapshot struct {
+	elems  []reflect.Value
+	closed bool
+}
+
+// Offsets into the runtime's hchan struct. Its first fields have kept this
+// layout since Go 1.0:
+//
+//	qcount   uint
+//	dataqsiz uint
+//	buf      unsafe.Pointer
+//	elemsize uint16
+//	closed   uint32
+//
+// The fields that follow differ between releases, so the receive index is
+// found relative to elemtype, which holds the element type's descriptor.
+const (
+	wordSize        = unsafe.Sizeof(uintptr(0))
+	hchanQcount     = 0
+	hchanDataqsiz   = wordSize
+	hchanBuf        = 2 * wordSize
+	hchanClosed     = 3*wordSize + 4
+	hchanSearchFrom = 4 * wordSize
+	hchanSearchTo   = 12 * wordSize
+)
+
+// readChanBuffer copies the buffered elements of v in receive order without
+// modifying the channel. It reports false when the elements hold pointers or
+// the runtime layout does not match what it expects.
+//
+// The channel lock is not taken, so an element may be copied while it is
+// being written. That is harmless for plain data, but a torn pointer would
+// hand the garbage collector and the printer an address that was never
+// allocated, so channels of elements with pointers are never read.
+func readChanBuffer(v reflect.Value) (chanSnapshot, bool) {
+	if hasPointers(v.Type().Elem()) {
+		return chanSnapshot{}, false
+	}
+	c := v.UnsafePointer()
+	qcount := *(*uint)(unsafe.Add(c, hchanQcount))
+	dataqsiz := *(*uint)(unsafe.Add(c, hchanDataqsiz))
+	buf := *(*unsafe.Pointer)(unsafe.Add(c, hchanBuf))
+	closed := *(*uint32)(unsafe.Add(c, hchanClosed)) != 0
+
+	if dataqsiz != uint(v.Cap()) || qcount > dataqsiz {
+		return chanSnapshot{}, false
+	}
+	if qcount == 0 {
+		return chanSnapshot{closed: closed}, true
+	}
+
+	elemType := v.Type().Elem()
+	typeWord := typeDescriptor(elemType)
+	for off := hchanSearchFrom; off+2*wordSize < hchanSearchTo; off += wordSize {
+		if *(*unsafe.Pointer)(unsafe.Add(c, off)) != typeWord {
+			continue
+		}
+		sendx := *(*uint)(unsafe.Add(c, off+wordSize))
+		recvx := *(*uint)(unsafe.Add(c, off+2*wordSize))
+		if sendx >= dataqsiz || recvx >= dataqsiz || (recvx+qcount)%dataqsiz != sendx {
+			return chanSnapshot{}, false
+		}
+
+		snap := chanSnapshot{closed: closed, elems: make([]reflect.Value, qcount)}
+		size := elemType.Size()
+		for i := uint(0); i < qcount; i++ {
+			slot := unsafe.Add(buf, uintptr((recvx+i)%dataqsiz)*size)
+			elem := reflect.New(elemType).Elem()
+			elem.Set(reflect.NewAt(elemType, slot).Elem())
+			snap.elems[i] = elem
+		}
+		return snap, true
+	}
+	return chanSnapshot{}, false
+}
+
+// hasPointers reports whether values of type t contain pointers, including
+// the hidden ones of strings, slices, maps, funcs and interfaces.
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// typeDescriptor returns the runtime type descriptor behind t, which is the
+// data word of the reflect.Type interface value.
+func typeDescriptor(t reflect.Type) unsafe.Pointer {
+	return (*[2]unsafe.Pointer)(unsafe.Pointer(&t))[1]
+}
@@ -0,0 +1,101 @@
+package godump
+
+import (
+	"reflect"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+func TestChanSummary(t *testing.T) {
+	d := newDumperT(t, WithoutHeader())
+
+	ch := make(chan int, 3)
+	ch <- 1
+	out := d.DumpStr(ch)
+	assert.Contains(t, out, "chan int(0x")
+	assert.Contains(t, out, ") dir=both elem=int len=1 cap=3\n")
+	assert.NotContains(t, out, "full")
+
+	ch <- 2
+	ch <- 3
+	assert.Contains(t, d.DumpStr(ch), "len=3 cap=3 full\n")
+
+	var recv <-chan string = make(chan string)
+	assert.Contains(t, d.DumpStr(recv), "dir=recv elem=string len=0 cap=0\n")
+
+	var send chan<- []byte = make(chan []byte, 1)
+	assert.Contains(t, d.DumpStr(send), "dir=send elem=[]uint8 len=0 cap=1\n")
+
+	var nilCh chan int
+	assert.Equal(t, "chan int(nil)\n", d.DumpStr(nilCh))
+}
+
+func TestChanBufferedElements(t *testing.T) {
+	type Job struct {
+		ID int
+	}
+	ch := make(chan Job, 4)
+	// Wrap the ring buffer so the oldest element is not at index 0.
+	for i := 1; i <= 4; i++ {
+		ch <- Job{ID: i}
+	}
+	<-ch
+	<-ch
+	ch <- Job{ID: 5}
+
+	out := newDumperT(t, WithoutHeader(), WithChannelBuffers()).DumpStr(ch)
+	assert.Contains(t, out, "len=3 cap=4 [\n")
+	assert.Contains(t, out, "  0 => #godump.Job {\n    +ID => 3 #int\n  }\n")
+	assert.Contains(t, out, "  1 => #godump.Job {\n    +ID => 4 #int\n  }\n")
+	assert.Contains(t, out, "  2 => #godump.Job {\n    +ID => 5 #int\n  }\n]\n")
+
+	// Reading the buffer must not consume anything.
+	assert.Equal(t, 3, len(ch))
+	assert.Equal(t, 3, (<-ch).ID)
+}
+
+func TestChanBufferedClosedAndLimits(t *testing.T) {
+	ch := make(chan float64, 3)
+	ch <- 1.5
+	ch <- 2.5
+	close(ch)
+
+	out := newDumperT(t, WithoutHeader(), WithChannelBuffers()).DumpStr(ch)
+	assert.Contains(t, out, "len=2 cap=3 closed [\n")
+	assert.Contains(t, out, `1 => 2.5 #float64`)
+
+	out = newDumperT(t, WithoutHeader(), WithChannelBuffers(), WithMaxItems(1)).DumpStr(ch)
+	assert.Contains(t, out, `0 => 1.5 #float64`)
+	assert.Contains(t, out, "... (truncated)")
+
+	empty := make(chan struct{}, 2)
+	assert.Contains(t, newDumperT(t, WithoutHeader(), WithChannelBuffers()).DumpStr(empty), "len=0 cap=2\n")
+
+	done := make(chan struct{}, 1)
+	done <- struct{}{}
+	out = newDumperT(t, WithoutHeader(), WithChannelBuffers()).DumpStr(done)
+	assert.Contains(t, out, "len=1 cap=1 full [\n")
+	assert.Contains(t, out, "0 => #struct {} {\n")
+}
+
+func TestChanBufferSkipsPointerElements(t *testing.T) {
+	type job struct {
+		ID   int
+		Name string
+	}
+	jobs := make(chan job, 2)
+	jobs <- job{ID: 1, Name: "a"}
+	strs := make(chan string, 2)
+	strs <- "a"
+
+	d := newDumperT(t, WithoutHeader(), WithChannelBuffers())
+	assert.Contains(t, d.DumpStr(jobs), "len=1 cap=2 (buffer unavailable)\n")
+	assert.Contains(t, d.DumpStr(strs), "len=1 cap=2 (buffer unavailable)\n")
+	assert.Equal(t, 1, len(jobs))
+
+	assert.False(t, hasPointers(reflect.TypeOf([2]struct{ A, B int }{})))
+	assert.False(t, hasPointers(reflect.TypeOf([0]*int{})))
+	assert.True(t, hasPointers(reflect.TypeOf(struct{ A []int }{})))
+	assert.True(t, hasPointers(reflect.TypeOf(new(any)).Elem()))
+}
@@ -0,0 +1,181 @@
+package godump
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WithCompact prints slices, maps and structs on a single line when their
+// rendering, including indentation, fits within maxWidth columns. Wider
+// values keep the multi-line layout. A maxWidth of 0 disables compact output.
+// @group Options
+//
+// Example: inline small values
+//
+//	// Default: 0 (disabled)
+//	type Point struct {
+//		X, Y int
+//	}
+//	d := godump.NewDumper(godump.WithCompact(80))
+//	d.Dump([]int{1, 2, 3}, Point{X: 1, Y: 2})
+//	// #[]int [1, 2, 3]
+//	// #godump.Point {X: 1, Y: 2}
+func WithCompact(maxWidth int) Option {
+	return func(d *Dumper) *Dumper {
+		if maxWidth >= 0 {
+			d.compactWidth = maxWidth
+		}
+		return d
+	}
+}
+
+// printCompact writes n on a single line when compact output is enabled and
+// n fits. It reports whether n was printed.
+func (d *Dumper) printCompact(w io.Writer, n *Node, indent int) bool {
+	if d.compactWidth <= 0 || !isComplexNode(n) {
+		return false
+	}
+	// A value fits on a line only if it has fewer nodes than the line has
+	// columns, so no more than that are inspected ahead of printing.
+	limit := d.compactWidth
+	if !prefetch(n, &limit) || !d.inlineable(n) {
+		return false
+	}
+	s, ok := d.inlineNode(n, indent, true, d.compactWidth-indent*indentWidth)
+	if ok {
+		fmt.Fprint(w, s)
+	}
+	return ok
+}
+
+// inlineNode renders n on one line within width visible columns. typed
+// reports whether the type must be shown; it is false for elements and fields
+// whose type is already given by their container. It reports false when n
+// does not fit, spans several lines, or is a reference that is shown more
+// than once and so needs an anchor.
+func (d *Dumper) inlineNode(n *Node, indent int, typed bool, width int) (string, bool) {
+	if width <= 0 || n.ID > 0 {
+		return "", false
+	}
+
+	if !d.inlineable(n) {
+		var sb strings.Builder
+		d.printNode(&sb, n, indent)
+		return d.fitInline(sb.String(), width)
+	}
+
+	switch n.Kind {
+	case NodeString, NodeScalar:
+		s := d.scalarString(n)
+		if typed {
+			s += d.colorize(RoleType, " #"+n.Type)
+		}
+		return d.fitInline(s, width)
+	case NodeInterface:
+		return d.inlineNode(n.Children[0], indent, true, width)
+	}
+
+	var sb strings.Builder
+	open, closing := "[", "]"
+	if n.Kind == NodeStruct || n.Kind == NodeMap {
+		open, closing = "{", "}"
+	}
+	if typed || strings.HasPrefix(n.Type, "*") {
+		sb.WriteString(d.colorize(RoleType, "#"+n.Type) + " ")
+	}
+	sb.WriteString(open)
+
+	// add appends one entry, failing once the line no longer fits.
+	count := 0
+	add := func(label string, child *Node) bool {
+		if count > 0 {
+			sb.WriteString(", ")
+		}
+		count++
+		sb.WriteString(label)
+		used := d.visibleWidth(sb.String())
+		s, ok := d.inlineNode(child, indent+1, false, width-used-len(closing))
+		if !ok {
+			return false
+		}
+		sb.WriteString(s)
+		return true
+	}
+	note := func(text string) {
+		if count > 0 {
+			sb.WriteString(", ")
+		}
+		count++
+		sb.WriteString(text)
+	}
+
+	for _, child := range n.Children {
+		var label string
+		switch n.Kind {
+		case NodeStruct:
+			label = d.colorize(RoleField, d.sanitize(child.Name))
+			if child.Unexported {
+				label = d.colorize(RoleVisibility, "-") + label
+			}
+			label += ": "
+			if child.Redacted {
+				note(label + d.colorize(RoleRedacted, "<redacted>"))
+				continue
+			}
+		case NodeMap:
+			label = d.colorize(RoleKey, d.sanitize(child.Name)) + ": "
+		}
+		if !add(label, child) {
+			return "", false
+		}
+	}
+	if n.Truncated {
+		note(d.colorize(RoleMuted, "... (truncated)"))
+	}
+	sb.WriteString(closing)
+	return d.fitInline(sb.String(), width)
+}
+
+// inlineable reports whether inlineNode renders n itself rather than
+// deferring to printNode, which handles hooks, errors, Stringers, nils,
+// depth limits and byte slices.
+func (d *Dumper) inlineable(n *Node) bool {
+	if n.MaxDepth || n.Redacted {
+		return false
+	}
+	// Addresses are only shown in the multi-line layout.
+	if d.showAddresses && (len(n.Addrs) > 0 || n.slice) {
+		return false
+	}
+	switch n.Kind {
+	case NodeInterface, NodeStruct, NodeMap, NodeList:
+		return true
+	case NodeString, NodeScalar:
+		return !strings.HasPrefix(n.Type, "*")
+	default:
+		return false
+	}
+}
+
+// isComplexNode reports whether n, or the value inside an interface node,
+// is a struct, map, slice or array.
+func isComplexNode(n *Node) bool {
+	for n.Kind == NodeInterface {
+		n = n.Children[0]
+	}
+	switch n.Kind {
+	case NodeStruct, NodeMap, NodeList, NodeBytes:
+		return true
+	default:
+		return false
+	}
+}
+
+// fitInline reports whether s is a single line of at most width visible columns.
+func (d *Dumper) fitInline(s string, width int) (string, bool) {
+	if strings.Contains(s, "\n") || d.visibleWidth(s) > width {
+		return "", false
+	}
+	return s, true
+}
@@ -0,0 +1,88 @@
+package godump
+
+import (
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+type compactPoint struct {
+	X, Y int
+}
+
+func TestCompactInlinesSmallValues(t *testing.T) {
+	d := newDumperT(t, WithoutHeader(), WithCompact(80))
+
+	assert.Equal(t, "#[]int [1, 2, 3]\n", d.DumpStr([]int{1, 2, 3}))
+	assert.Equal(t, "#godump.compactPoint {X: 1, Y: 2}\n", d.DumpStr(compactPoint{X: 1, Y: 2}))
+	assert.Equal(t, "#*godump.compactPoint {X: 3, Y: 4}\n", d.DumpStr(&compactPoint{X: 3, Y: 4}))
+	assert.Equal(t, "#map[string]int {a: 1, b: 2}\n", d.DumpStr(map[string]int{"b": 2, "a": 1}))
+	assert.Equal(t, "#[]string []\n", d.DumpStr([]string{}))
+	assert.Equal(t, "#[]interface {} [1 #int, \"a\" #string, interface {}(nil)]\n", d.DumpStr([]any{1, "a", nil}))
+}
+
+func TestCompactFallsBackWhenTooWide(t *testing.T) {
+	points := []compactPoint{{X: 1, Y: 2}, {X: 3, Y: 4}, {X: 5, Y: 6}}
+
+	out := newDumperT(t, WithoutHeader(), WithCompact(40)).DumpStr(points)
+	assert.Equal(t, `#[]godump.compactPoint [
+  0 => #godump.compactPoint {X: 1, Y: 2}
+  1 => #godump.compactPoint {X: 3, Y: 4}
+  2 => #godump.compactPoint {X: 5, Y: 6}
+]
+`, out)
+
+	out = newDumperT(t, WithoutHeader(), WithCompact(0)).DumpStr(compactPoint{X: 1})
+	assert.Contains(t, out, "+X => 1 #int")
+}
+
+func TestCompactKeepsMarkers(t *testing.T) {
+	type Account struct {
+		User     string
+		Password string
+		secret   string
+	}
+
+	d := newDumperT(t, WithoutHeader(), WithCompact(120), WithRedactFields("Password"), WithMaxItems(2), WithMaxStringLen(3))
+	assert.Equal(t, "#godump.Account {User: \"bob…\", Password: <redacted>, -secret: \"x\"}\n",
+		d.DumpStr(Account{User: "bobby", Password: "hunter2", secret: "x"}))
+	assert.Equal(t, "#[]int [1, 2, ... (truncated)]\n", d.DumpStr([]int{1, 2, 3}))
+
+	out := newDumperT(t, WithoutHeader(), WithCompact(120), WithMaxDepth(1)).DumpStr([][][]int{{{1}}})
+	assert.Equal(t, "#[][][]int [... (max depth)]\n", out)
+}
+
+func TestCompactKeepsColors(t *testing.T) {
+	unsetColorEnv(t)
+	t.Setenv("FORCE_COLOR", "3")
+	out := NewDumper(WithoutHeader(), WithCompact(80)).DumpStr([]int{1, 2})
+	assert.Contains(t, out, colorCyan+"1")
+	assert.Equal(t, "#[]int [1, 2]\n", stripANSI(out))
+}
+
+func TestCompactLeavesMultilineValues(t *testing.T) {
+	type Payload struct {
+		Body []byte
+	}
+
+	out := newDumperT(t, WithoutHeader(), WithCompact(200)).DumpStr(Payload{Body: []byte("hi")})
+	assert.Contains(t, out, "+Body => ([]uint8) (len=2 cap=2) {")
+	assert.True(t, strings.Count(out, "\n") > 2)
+}
+
+func TestCompactAndReferences(t *testing.T) {
+	type Pair struct {
+		A, B []int
+	}
+	shared := []int{1}
+
+	out := newDumperT(t, WithoutHeader(), WithCompact(80)).DumpStr(Pair{A: shared, B: shared})
+	assert.Contains(t, out, "+A => &1 #[]int [")
+	assert.Contains(t, out, "+B => ↩︎ &1")
+
+	m := map[string]any{}
+	m["self"] = m
+	out = newDumperT(t, WithoutHeader(), WithCompact(80)).DumpStr(m)
+	assert.Contains(t, out, "self => ↩︎ &1")
+}
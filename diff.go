@@ -3,11 +3,8 @@ package godump
 import (
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
 	"reflect"
 	"strings"
-	"text/tabwriter"
 )
 
 // Diff prints a diff between two values to stdout.
@@ -46,7 +43,7 @@ func Diff(a, b any) {
 //	// +   a => 2 #int
 //	// + }
 func (d *Dumper) Diff(a, b any) {
-	fmt.Fprint(d.writer, d.DiffStr(a, b))
+	writeLocked(d.writer, d.DiffStr(a, b))
 }
 
 // DiffStr returns a string diff between two values.
@@ -87,6 +84,7 @@ func DiffStr(a, b any) string {
 //	// +   a => 2 #int
 //	// + }
 func (d *Dumper) DiffStr(a, b any) string {
+	d = d.clone()
 	var sb strings.Builder
 	d.printDiffHeader(&sb)
 	d.ensureColorizer()
@@ -105,7 +103,8 @@ func (d *Dumper) DiffStr(a, b any) string {
 	return sb.String()
 }
 
-// DiffHTML returns an HTML diff between two values.
+// DiffHTML returns an HTML diff between two values, with all text
+// HTML-escaped.
 // @group Diff
 //
 // Example: HTML diff
@@ -119,7 +118,8 @@ func DiffHTML(a, b any) string {
 	return defaultDumper.DiffHTML(a, b)
 }
 
-// DiffHTML returns an HTML diff between two values.
+// DiffHTML returns an HTML diff between two values, with all text
+// HTML-escaped.
 // @group Diff
 //
 // Example: HTML diff with a custom dumper
@@ -132,15 +132,9 @@ func DiffHTML(a, b any) string {
 //	// (html diff)
 func (d *Dumper) DiffHTML(a, b any) string {
 	var sb strings.Builder
-	sb.WriteString(`<div style='background-color:black;'><pre style="background-color:black; color:white; padding:5px; border-radius: 5px">` + "\n")
-
-	htmlDumper := d.clone()
-	if !htmlDumper.disableColor {
-		htmlDumper.colorizer = colorizeHTML
-	}
-
-	sb.WriteString(htmlDumper.DiffStr(a, b))
-	sb.WriteString("</pre></div>")
+	sb.WriteString(d.htmlOpen())
+	sb.WriteString(d.htmlClone().DiffStr(a, b))
+	sb.WriteString(d.htmlClose())
 	return sb.String()
 }
 
@@ -155,8 +149,8 @@ func (d *Dumper) diffDumps(a, b any) diffDumpPair {
 	rightDump := d.dumpStrNoHeader(b)
 
 	if reflect.TypeOf(a) != reflect.TypeOf(b) {
-		leftType := fmt.Sprintf("type: %s", d.typeStringForAny(a))
-		rightType := fmt.Sprintf("type: %s", d.typeStringForAny(b))
+		leftType := d.colorize(colorPlain, "type: "+d.typeStringForAny(a))
+		rightType := d.colorize(colorPlain, "type: "+d.typeStringForAny(b))
 		leftDump = leftType + "\n" + leftDump
 		rightDump = rightType + "\n" + rightDump
 	}
@@ -167,12 +161,8 @@ func (d *Dumper) diffDumps(a, b any) diffDumpPair {
 // dumpStrNoHeader renders a dump without the header line.
 func (d *Dumper) dumpStrNoHeader(vs ...any) string {
 	d.ensureColorizer()
-	state := newDumpState()
-
 	var sb strings.Builder
-	tw := tabwriter.NewWriter(&sb, 0, 0, 1, ' ', 0)
-	d.writeDump(tw, state, vs...)
-	tw.Flush()
+	d.writeDump(&sb, vs...)
 	return sb.String()
 }
 
@@ -181,20 +171,11 @@ func (d *Dumper) printDiffHeader(out io.Writer) {
 	if d.disableHeader {
 		return
 	}
-	file, line := d.findFirstNonInternalFrame(d.skippedStackFrames)
+	file, line, pc := d.findCaller(d.skippedStackFrames)
 	if file == "" {
 		return
 	}
-
-	relPath := file
-	if wd, err := os.Getwd(); err == nil {
-		if rel, err := filepath.Rel(wd, file); err == nil {
-			relPath = rel
-		}
-	}
-
-	header := fmt.Sprintf("<#diff // %s:%d", relPath, line)
-	fmt.Fprintln(out, d.colorize(colorGray, header))
+	fmt.Fprintln(out, d.colorize(RoleHeader, "<#diff // "+d.headerText("diff", file, line, pc)))
 }
 
 // typeStringForAny returns a displayable type for a value.
@@ -277,9 +258,9 @@ func diffLines(a, b []string) []diffLine {
 func (d *Dumper) diffPrefix(kind diffKind) string {
 	switch kind {
 	case diffDelete:
-		return d.colorize(colorRed, "-") + " "
+		return d.colorize(RoleDiffDelSym, "-") + " "
 	case diffInsert:
-		return d.colorize(colorGreen, "+") + " "
+		return d.colorize(RoleDiffAddSym, "+") + " "
 	default:
 		return "  "
 	}
@@ -287,26 +268,32 @@ func (d *Dumper) diffPrefix(kind diffKind) string {
 
 // diffTintLine tints a full diff line based on change type.
 func (d *Dumper) diffTintLine(line string, kind diffKind) string {
-	if d.disableColor {
+	if d.disableColor || d.colorDepth == colorDepthNone {
 		return line
 	}
 
 	switch kind {
 	case diffDelete:
-		return d.tintBackgroundLine(line, colorRedBg, "#221010")
+		return d.tintBackgroundLine(line, RoleDiffDel)
 	case diffInsert:
-		return d.tintBackgroundLine(line, colorGreenBg, "#102216")
+		return d.tintBackgroundLine(line, RoleDiffAdd)
 	default:
 		return line
 	}
 }
 
-// tintBackgroundLine applies a full-line background while preserving text colors.
-func (d *Dumper) tintBackgroundLine(line, bgCode, bgHex string) string {
-	if isHTMLLine(line) {
-		return `<span style="background-color:` + bgHex + `; display:block; width:100%;">` + line + `</span>`
+// tintBackgroundLine applies the background of role to a full line while
+// preserving text colors.
+func (d *Dumper) tintBackgroundLine(line string, role Role) string {
+	style := d.currentTheme().Styles[role]
+	switch {
+	case d.htmlOutput && d.htmlClasses:
+		return `<span class="gd-` + string(role) + `">` + line + `</span>`
+	case d.htmlOutput:
+		return `<span style="background-color:` + style.Color + `; display:block; width:100%;">` + line + `</span>`
 	}
 
+	bgCode := downsampleANSI(style.ANSI, d.colorDepth)
 	if strings.Contains(line, string(ansiEscape)+"[") {
 		return bgCode + strings.ReplaceAll(line, colorReset, colorReset+bgCode) + ansiEraseLine + colorReset
 	}
@@ -347,30 +334,42 @@ func stripANSI(s string) string {
 	return b.String()
 }
 
+// htmlSpanPrefixes are the starts of the span tags the HTML colorizers emit.
+var htmlSpanPrefixes = []string{`<span style="`, `<span class="gd-`}
+
 // stripHTMLSpans removes color span tags while preserving content.
 func stripHTMLSpans(s string) string {
-	const spanPrefix = `<span style="color:`
-	const spanSuffix = `">`
+	if !isHTMLLine(s) {
+		return s
+	}
 
-	for {
-		start := strings.Index(s, spanPrefix)
-		if start == -1 {
-			break
-		}
-		rest := s[start+len(spanPrefix):]
-		before, _, ok := strings.Cut(rest, spanSuffix)
-		if !ok {
-			break
+	const spanSuffix = `">`
+	for _, spanPrefix := range htmlSpanPrefixes {
+		for {
+			start := strings.Index(s, spanPrefix)
+			if start == -1 {
+				break
+			}
+			rest := s[start+len(spanPrefix):]
+			before, _, ok := strings.Cut(rest, spanSuffix)
+			if !ok {
+				break
+			}
+			cut := start + len(spanPrefix) + len(before) + len(spanSuffix)
+			s = s[:start] + s[cut:]
 		}
-		cut := start + len(spanPrefix) + len(before) + len(spanSuffix)
-		s = s[:start] + s[cut:]
 	}
 	return strings.ReplaceAll(s, "</span>", "")
 }
 
 // isHTMLLine reports whether the line contains HTML color spans.
 func isHTMLLine(line string) bool {
-	return strings.Contains(line, `<span style="color:`)
+	for _, prefix := range htmlSpanPrefixes {
+		if strings.Contains(line, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
 // splitLines splits a string into lines while normalizing CRLF and trimming a trailing newline.
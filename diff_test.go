@@ -92,7 +92,7 @@ func TestDiffTopLevelHelpers(t *testing.T) {
 func TestDiffHTML(t *testing.T) {
 	html := DiffHTML(map[string]int{"a": 1}, map[string]int{"a": 2})
 	assert.Contains(t, html, `<span style="color:`)
-	assert.Contains(t, html, "<#diff //")
+	assert.Contains(t, html, "&lt;#diff //")
 	assert.Contains(t, html, "-")
 	assert.Contains(t, html, "+")
 }
@@ -118,8 +118,8 @@ func TestDiffHTMLNoColor(t *testing.T) {
 	out := NewDumper(WithoutColor()).DiffHTML("a", "b")
 	assert.NotContains(t, out, `<span style="color:`)
 	assert.NotContains(t, out, `<span style="background-color:`)
-	assert.Contains(t, out, `"a"`)
-	assert.Contains(t, out, `"b"`)
+	assert.Contains(t, out, `&#34;a&#34;`)
+	assert.Contains(t, out, `&#34;b&#34;`)
 }
 
 func TestDiffStrNoHeaderWhenNoCaller(t *testing.T) {
@@ -146,7 +146,7 @@ func TestDiffTintHelpers(t *testing.T) {
 	d := NewDumper()
 	d.colorizer = colorizeUnstyled
 
-	line := d.tintBackgroundLine(string(ansiEscape)+"[33mabc"+string(ansiEscape)+"[0m", colorRedBg, "#3a0d0d")
+	line := d.tintBackgroundLine(string(ansiEscape)+"[33mabc"+string(ansiEscape)+"[0m", RoleDiffDel)
 	assert.Contains(t, line, "abc")
 
 	assert.Equal(t, "abc", stripANSI(string(ansiEscape)+"[31mabc"+string(ansiEscape)+"[0m"))
@@ -163,6 +163,10 @@ func TestDiffTintHelpers(t *testing.T) {
 	htmlBroken := `<span style="color:#999"broken`
 	assert.Equal(t, htmlBroken, stripHTMLSpans(htmlBroken))
 
-	line = d.tintBackgroundLine(html, colorRedBg, "#3a0d0d")
-	assert.Contains(t, line, "x")
+	// Text output is tinted with ANSI even when the data looks like HTML.
+	line = d.tintBackgroundLine(html, RoleDiffDel)
+	assert.True(t, strings.HasPrefix(line, string(ansiEscape)+"["))
+	assert.Contains(t, line, html)
+	line = d.htmlClone().tintBackgroundLine(html, RoleDiffDel)
+	assert.True(t, strings.HasPrefix(line, `<span style="background-color:`))
 }
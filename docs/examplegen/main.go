@@ -396,6 +396,7 @@ func writeMain(base string, fd *FuncDoc, importPath string) error {
 		{token: "godump.", path: "github.com/goforj/godump"},
 		{token: "rand.", path: "crypto/rand"},
 		{token: "base64.", path: "encoding/base64"},
+		{token: "reflect.", path: "reflect"},
 	}
 	for _, ex := range fd.Examples {
 		for _, rule := range importRules {
@@ -0,0 +1,176 @@
+package godump
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// stackMethods are the method names probed for stack traces, in order.
+var stackMethods = []string{"StackTrace", "Stack"}
+
+// inspectError builds the node for an error: its message, its unwrap chain
+// and any stack trace it carries. It returns nil when v is not shown as an
+// error.
+func (d *Dumper) inspectError(v reflect.Value, depth int, state *dumpState) *Node {
+	err, ok := d.asError(v)
+	if !ok {
+		return nil
+	}
+	v = forceExported(v)
+
+	n := &Node{Kind: NodeError, Type: d.typeString(v.Type())}
+	n.Value, n.Truncated = truncateRunes(err.Error(), d.maxStringLen)
+	wrapped := unwrapErrors(err)
+	stack, hasStack := errorStack(v)
+	if len(wrapped) == 0 && !hasStack {
+		return n
+	}
+	if depth >= d.maxDepth {
+		n.MaxDepth = true
+		return n
+	}
+
+	msg := &Node{Kind: NodeString, Name: "Error", Value: n.Value, Raw: err.Error(), Truncated: n.Truncated}
+	n.Children = append(n.Children, msg)
+
+	var unwrap *Node
+	switch {
+	case len(wrapped) == 1 && !isMultiUnwrapper(err):
+		unwrap = d.inspectValue(reflect.ValueOf(wrapped[0]), depth+1, state)
+	case len(wrapped) > 0:
+		unwrap = d.inspectValue(reflect.ValueOf(wrapped), depth+1, state)
+	}
+	if unwrap != nil {
+		unwrap.Name = "Unwrap"
+		n.Children = append(n.Children, unwrap)
+	}
+
+	if hasStack {
+		n.Children = append(n.Children, d.inspectStack(stack))
+	}
+	return n
+}
+
+// asError returns v as an error when it is printed as one.
+func (d *Dumper) asError(v reflect.Value) (error, bool) {
+	if d.disableStringer || v.Kind() == reflect.Interface || !d.plan(v.Type()).isError {
+		return nil, false
+	}
+
+	v = forceExported(v)
+	if !v.CanInterface() {
+		return nil, false
+	}
+	err, ok := v.Interface().(error)
+	return err, ok
+}
+
+// isMultiUnwrapper reports whether err implements Unwrap() []error, as errors.Join does.
+func isMultiUnwrapper(err error) bool {
+	_, ok := err.(interface{ Unwrap() []error })
+	return ok
+}
+
+// unwrapErrors returns the non-nil errors wrapped by err.
+func unwrapErrors(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := u.Unwrap(); inner != nil {
+			return []error{inner}
+		}
+	case interface{ Unwrap() []error }:
+		var out []error
+		for _, inner := range u.Unwrap() {
+			if inner != nil {
+				out = append(out, inner)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// errorStack calls a StackTrace() or Stack() method on v when one exists.
+func errorStack(v reflect.Value) (reflect.Value, bool) {
+	for _, name := range stackMethods {
+		m := v.MethodByName(name)
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+			continue
+		}
+		out := m.Call(nil)[0]
+		if isNil(out) {
+			continue
+		}
+		return out, true
+	}
+	return reflect.Value{}, false
+}
+
+// stackFrames converts a stack trace value into printable frame descriptions.
+// It understands program counter slices (such as pkg/errors.StackTrace),
+// []runtime.Frame, and textual traces returned as string or []byte.
+func stackFrames(stack reflect.Value) []string {
+	switch {
+	case stack.Kind() == reflect.String:
+		return stackLines(stack.String())
+	case stack.Kind() == reflect.Slice && stack.Type().Elem().Kind() == reflect.Uint8:
+		return stackLines(string(stack.Bytes()))
+	case stack.Kind() == reflect.Slice && stack.Type().Elem().Kind() == reflect.Uintptr:
+		pcs := make([]uintptr, stack.Len())
+		for i := range pcs {
+			pcs[i] = uintptr(stack.Index(i).Uint())
+		}
+		var out []string
+		frames := runtime.CallersFrames(pcs)
+		for {
+			frame, more := frames.Next()
+			out = append(out, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+			if !more {
+				return out
+			}
+		}
+	case stack.Kind() == reflect.Slice:
+		out := make([]string, 0, stack.Len())
+		for i := 0; i < stack.Len(); i++ {
+			elem := forceExported(stack.Index(i))
+			if frame, ok := elem.Interface().(runtime.Frame); ok {
+				out = append(out, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+				continue
+			}
+			out = append(out, fmt.Sprint(elem.Interface()))
+		}
+		return out
+	default:
+		return stackLines(fmt.Sprint(forceExported(stack).Interface()))
+	}
+}
+
+// stackLines splits a textual stack trace into trimmed, non-empty lines.
+func stackLines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// inspectStack builds the "Stack" node of an error as a list of frames.
+func (d *Dumper) inspectStack(stack reflect.Value) *Node {
+	frames := stackFrames(stack)
+	n := &Node{Kind: NodeList, Type: d.typeString(stack.Type()), Name: "Stack", Len: len(frames)}
+	for i, frame := range frames {
+		if i >= d.maxItems {
+			n.Truncated = true
+			break
+		}
+		n.Children = append(n.Children, &Node{Kind: NodeText, Name: strconv.Itoa(i), Value: frame})
+	}
+	return n
+}
@@ -0,0 +1,155 @@
+package godump
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+type joinedErrors []error
+
+func (j joinedErrors) Error() string {
+	msgs := make([]string, 0, len(j))
+	for _, err := range j {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (j joinedErrors) Unwrap() []error { return j }
+
+type stackFrame uintptr
+
+type pcStackTrace []stackFrame
+
+type tracedError struct {
+	msg   string
+	stack pcStackTrace
+}
+
+func (e *tracedError) Error() string { return e.msg }
+
+func (e *tracedError) StackTrace() pcStackTrace { return e.stack }
+
+func newTracedError(msg string) *tracedError {
+	pcs := make([]uintptr, 8)
+	n := runtime.Callers(1, pcs)
+	stack := make(pcStackTrace, n)
+	for i := 0; i < n; i++ {
+		stack[i] = stackFrame(pcs[i])
+	}
+	return &tracedError{msg: msg, stack: stack}
+}
+
+type textStackError struct{}
+
+func (textStackError) Error() string { return "text stack" }
+
+func (textStackError) Stack() []byte { return []byte("main.main()\n\t/app/main.go:10\n\n") }
+
+type frameStackError struct{}
+
+func (frameStackError) Error() string { return "frame stack" }
+
+func (frameStackError) Stack() []runtime.Frame {
+	return []runtime.Frame{{Function: "main.handler", File: "/app/handler.go", Line: 42}}
+}
+
+func TestErrorLeaf(t *testing.T) {
+	out := newDumperT(t, WithoutHeader()).DumpStr(errors.New("boom"))
+	assert.Equal(t, "\"boom\" #*errors.errorString\n", out)
+}
+
+func TestErrorWrapChain(t *testing.T) {
+	root := &fs.PathError{Op: "open", Path: "/etc/app.yml", Err: errors.New("no such file")}
+	err := fmt.Errorf("load config: %w", root)
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(err)
+	assert.Equal(t, `#*fmt.wrapError {
+  Error  => "load config: open /etc/app.yml: no such file"
+  Unwrap => #*fs.PathError {
+    Error  => "open /etc/app.yml: no such file"
+    Unwrap => "no such file" #*errors.errorString
+  }
+}
+`, out)
+}
+
+func TestErrorMultiUnwrap(t *testing.T) {
+	err := joinedErrors{errors.New("first"), nil, fmt.Errorf("second: %w", errors.New("cause"))}
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(err)
+	assert.Contains(t, out, "#[]error {")
+	assert.Contains(t, out, `Error  => "first\nsecond: cause"`)
+	assert.Contains(t, out, "Unwrap => #[]error [")
+	assert.Contains(t, out, `0 => "first" #*errors.errorString`)
+	assert.Contains(t, out, "1 => #*fmt.wrapError {")
+	assert.Contains(t, out, `Unwrap => "cause" #*errors.errorString`)
+}
+
+func TestErrorAsStructField(t *testing.T) {
+	type Response struct {
+		Status int
+		Err    error
+	}
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(Response{Status: 500, Err: fmt.Errorf("handler: %w", errors.New("db down"))})
+	assert.Contains(t, out, "+Err    => #*fmt.wrapError {")
+	assert.Contains(t, out, `    Unwrap => "db down" #*errors.errorString`)
+
+	out = newDumperT(t, WithoutHeader()).DumpStr(Response{Status: 200})
+	assert.Contains(t, out, "+Err    => error(nil)")
+}
+
+func TestErrorStackTraceFrames(t *testing.T) {
+	err := newTracedError("traced")
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(err)
+	assert.Contains(t, out, "#*godump.tracedError {")
+	assert.Contains(t, out, `Error => "traced"`)
+	assert.Contains(t, out, "Stack => #[]godump.stackFrame [")
+	assert.Contains(t, out, "godump.newTracedError")
+	assert.Contains(t, out, "errors_test.go:")
+}
+
+func TestErrorStackVariants(t *testing.T) {
+	out := newDumperT(t, WithoutHeader()).DumpStr(textStackError{})
+	assert.Contains(t, out, "0 => main.main()")
+	assert.Contains(t, out, "1 => /app/main.go:10")
+	assert.NotContains(t, out, "2 =>")
+
+	out = newDumperT(t, WithoutHeader()).DumpStr(frameStackError{})
+	assert.Contains(t, out, "0 => main.handler /app/handler.go:42")
+
+	frames := stackFrames(reflect.ValueOf("a\n\n  b  "))
+	assert.Equal(t, []string{"a", "b"}, frames)
+
+	frames = stackFrames(reflect.ValueOf([]string{"x", "y"}))
+	assert.Equal(t, []string{"x", "y"}, frames)
+
+	frames = stackFrames(reflect.ValueOf(42))
+	assert.Equal(t, []string{"42"}, frames)
+}
+
+func TestErrorRenderingLimits(t *testing.T) {
+	err := fmt.Errorf("a: %w", fmt.Errorf("b: %w", errors.New("c")))
+
+	out := newDumperT(t, WithoutHeader(), WithMaxDepth(1)).DumpStr(err)
+	assert.Contains(t, out, "Unwrap => ... (max depth)")
+
+	out = newDumperT(t, WithoutHeader(), WithMaxItems(1)).DumpStr(textStackError{})
+	assert.Contains(t, out, "0 => main.main()")
+	assert.Contains(t, out, "... (truncated)")
+
+	out = newDumperT(t, WithoutHeader(), WithDisableStringer(true)).DumpStr(errors.New("raw"))
+	assert.Contains(t, out, "#*errors.errorString {")
+	assert.Contains(t, out, `-s => "raw" #string`)
+}
@@ -0,0 +1,18 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// DarkTheme returns the default theme, for dark terminals and pages. Each
+	// call returns a new copy, so changing its Styles affects no other dumper.
+
+	// Example: dim type annotations in the default theme
+	theme := godump.DarkTheme()
+	theme.Styles[godump.RoleType] = godump.Style{ANSI: "\033[2m", Color: "#666"}
+	d := godump.NewDumper(godump.WithTheme(theme))
+	d.Dump(1)
+	// 1 #int
+}
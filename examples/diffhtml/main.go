@@ -6,7 +6,8 @@ package main
 import "github.com/goforj/godump"
 
 func main() {
-	// DiffHTML returns an HTML diff between two values.
+	// DiffHTML returns an HTML diff between two values, with all text
+	// HTML-escaped.
 
 	// Example: HTML diff with a custom dumper
 	d := godump.NewDumper()
@@ -6,7 +6,9 @@ package main
 import "github.com/goforj/godump"
 
 func main() {
-	// Dump prints the values to stdout with colorized output.
+	// Dump prints the values to the configured writer with colorized output.
+	// Output is streamed as it is rendered instead of being buffered in full, and
+	// concurrent dumps to the same writer are written one after another.
 
 	// Example: print with a custom dumper
 	d := godump.NewDumper()
@@ -0,0 +1,24 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"context"
+	"github.com/goforj/godump"
+	"time"
+)
+
+func main() {
+	// DumpContext prints the values like Dump, stopping early with a budget
+	// exceeded marker when ctx is done. The dumper's other limits still apply.
+
+	// Example: dump within a request's deadline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	d := godump.NewDumper(godump.WithMaxNodes(10000))
+	d.DumpContext(ctx, map[string]int{"a": 1})
+	// #map[string]int {
+	//   a => 1 #int
+	// }
+}
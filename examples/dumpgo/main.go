@@ -0,0 +1,17 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// DumpGo prints the values as gofmt-formatted Go literals to the configured writer.
+
+	// Example: print a Go literal with a custom dumper
+	d := godump.NewDumper()
+	d.DumpGo(map[string]int{"a": 1})
+	// map[string]int{
+	// 	"a": 1,
+	// }
+}
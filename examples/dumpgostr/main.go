@@ -0,0 +1,19 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// DumpGoStr returns the values as gofmt-formatted Go literals.
+	// Values reached through more than one pointer are hoisted into variables of
+	// an immediately invoked function, unexported and redacted fields become
+	// comments, and cycles that cannot be expressed are emitted as annotated nils.
+
+	// Example: get a Go literal with a custom dumper
+	d := godump.NewDumper()
+	out := d.DumpGoStr(int8(3))
+	_ = out
+	// int8(3)
+}
@@ -9,7 +9,8 @@ import (
 )
 
 func main() {
-	// DumpHTML dumps the values as HTML with colorized output.
+	// DumpHTML dumps the values as HTML with colorized output. All text is
+	// HTML-escaped, so the result can be embedded in a page as-is.
 
 	// Example: dump HTML with a custom dumper
 	d := godump.NewDumper()
@@ -0,0 +1,23 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"github.com/goforj/godump"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	// DumpHTMLFile writes the values as a standalone interactive HTML page to
+	// path, rendered with the dumper's options.
+
+	// Example: write a page with a custom dumper
+	d := godump.NewDumper(godump.WithRedactSensitive())
+	v := map[string]string{"token": "secret"}
+	path := filepath.Join(os.TempDir(), "dump.html")
+	if err := d.DumpHTMLFile(path, v); err != nil {
+		panic(err)
+	}
+}
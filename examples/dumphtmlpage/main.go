@@ -0,0 +1,18 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// DumpHTMLPage returns the values as a self-contained HTML document with an
+	// interactive viewer, rendered with the dumper's options.
+
+	// Example: interactive page with a custom dumper
+	d := godump.NewDumper(godump.WithMaxItems(50))
+	v := map[string][]int{"a": {1, 2}}
+	page := d.DumpHTMLPage(v)
+	_ = page
+	// <!DOCTYPE html> ... (interactive viewer)
+}
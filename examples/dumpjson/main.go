@@ -8,7 +8,7 @@ import "github.com/goforj/godump"
 func main() {
 	// DumpJSON dumps the values as a pretty-printed JSON string.
 	// If there is more than one value, they are dumped as a JSON array.
-	// It returns an error string if marshaling fails.
+	// It prints an error object if encoding fails.
 
 	// Example: print JSON
 	v := map[string]int{"a": 1}
@@ -0,0 +1,15 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// HighContrastTheme returns a theme with bright, bold colors on black.
+
+	// Example: bold colors for presentations
+	d := godump.NewDumper(godump.WithTheme(godump.HighContrastTheme()))
+	d.Dump(true)
+	// true #bool
+}
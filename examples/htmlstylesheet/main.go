@@ -0,0 +1,19 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// HTMLStylesheet returns the stylesheet of DarkTheme for the classes emitted
+	// with WithHTMLTheme. Colors are CSS custom properties on the .godump block,
+	// such as --gd-string, so a light theme or dark mode toggle only needs to
+	// override them.
+
+	// Example: override colors for a light page
+	css := godump.HTMLStylesheet() + `
+	.godump { --gd-bg: #fff; --gd-fg: #222; --gd-string: #1a7f37; }`
+	d := godump.NewDumper(godump.WithHTMLTheme(css))
+	_ = d.DumpHTML(map[string]int{"a": 1})
+}
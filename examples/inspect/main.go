@@ -0,0 +1,24 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"github.com/goforj/godump"
+)
+
+func main() {
+	// Inspect returns the node tree the dumper renders for v, with all of its
+	// options applied.
+
+	// Example: inspect with options
+	type User struct {
+		Name     string
+		Password string
+	}
+	d := godump.NewDumper(godump.WithRedactFields("Password"))
+	n := d.Inspect(&User{Name: "Alice", Password: "hunter2"})
+	fmt.Println(n.Type, n.Children[1].Name, n.Children[1].Redacted)
+	// *main.User Password true
+}
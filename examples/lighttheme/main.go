@@ -0,0 +1,16 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// LightTheme returns a theme with darker colors that stay readable on light
+	// backgrounds.
+
+	// Example: readable output on a light terminal
+	d := godump.NewDumper(godump.WithTheme(godump.LightTheme()))
+	d.Dump("hi")
+	// "hi" #string
+}
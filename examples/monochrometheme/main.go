@@ -0,0 +1,19 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// MonochromeTheme returns a theme with no colors, only bold, dim and
+	// underlined text, for terminals and pages where colors are unavailable or
+	// unwanted.
+
+	// Example: bold keys without colors
+	d := godump.NewDumper(godump.WithTheme(godump.MonochromeTheme()))
+	d.Dump(map[string]int{"a": 1})
+	// #map[string]int {
+	//   a => 1 #int
+	// }
+}
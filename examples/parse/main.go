@@ -0,0 +1,33 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"github.com/goforj/godump"
+)
+
+func main() {
+	// Parse reads text written by Dump, DumpStr or Fdump, plain or with ANSI
+	// colors, back into node trees, one [ParsedDump] per "<#dump" header. Lines
+	// that are not part of a dump, such as other log output, and Diff output are
+	// skipped.
+	//
+	// Nodes hold the text as printed: strings and labels keep the escapes added
+	// for control and invisible characters, and a string ending in "…" is taken
+	// as cut by WithMaxStringLen. Compact single-line values are read on a best
+	// effort basis, and their elements only have a Type when it can be derived
+	// from the container's type. The returned nodes can be passed to Dump,
+	// DumpHTML, DumpJSON or Diff to render them again.
+
+	// Example: read a dump from a log
+	log := "<#dump // main.go:12\n#main.User {\n  +Name => \"Alice\" #string\n}\n"
+	dumps, err := godump.Parse(log)
+	if err != nil {
+		panic(err)
+	}
+	user := dumps[0].Values[0]
+	fmt.Println(dumps[0].File, user.Type, user.Children[0].Value)
+	// main.go main.User Alice
+}
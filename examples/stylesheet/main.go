@@ -0,0 +1,15 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// Stylesheet returns the theme as a stylesheet for the classes emitted with
+	// WithHTMLTheme.
+
+	// Example: style class-based HTML with the light theme
+	d := godump.NewDumper(godump.WithHTMLTheme(godump.LightTheme().Stylesheet()))
+	_ = d.DumpHTML(map[string]int{"a": 1})
+}
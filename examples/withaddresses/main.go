@@ -0,0 +1,31 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithAddresses annotates pointers, maps, slices and interfaces with the
+	// address they refer to, and slices with their length and capacity. A value
+	// whose memory overlaps one printed earlier, such as two slices sharing a
+	// backing array, is marked with "aliases &N" pointing at the earlier value's
+	// anchor. Addresses change between runs, so leave this disabled for golden
+	// or snapshot tests, or turn it back off with [WithoutAddresses].
+
+	// Example: show addresses and aliasing
+	// Default: disabled
+	base := make([]int, 3, 4)
+	view := base[1:]
+	d := godump.NewDumper(godump.WithAddresses())
+	d.Dump(base, view)
+	// &1 #[]int @0xc000018180 len=3 cap=4 [
+	//   0 => 0 #int
+	//   1 => 0 #int
+	//   2 => 0 #int
+	// ]
+	// #[]int @0xc000018188 len=2 cap=3 aliases &1 [
+	//   0 => 0 #int
+	//   1 => 0 #int
+	// ]
+}
@@ -0,0 +1,28 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithChannelBuffers shows the elements waiting in buffered channels, oldest
+	// first, along with whether the channel is closed. Elements are copied out of
+	// the channel's ring buffer without receiving them, so the channel is left
+	// untouched. The copy is taken without the channel lock, so an element being
+	// sent or received at the same time may be shown half-written. Only element
+	// types without pointers are read, such as numbers and structs of numbers;
+	// other channels show "(buffer unavailable)" after their len and cap.
+
+	// Example: show buffered channel elements
+	// Default: disabled
+	ch := make(chan int, 4)
+	ch <- 1
+	ch <- 2
+	d := godump.NewDumper(godump.WithChannelBuffers())
+	d.Dump(ch)
+	// chan int(0xc000022120) dir=both elem=int len=2 cap=4 [
+	//   0 => 1 #int
+	//   1 => 2 #int
+	// ]
+}
@@ -0,0 +1,25 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithClosureCaptures lists the names and types of the variables captured by
+	// closures below the function name. Captures are located through the DWARF
+	// debug information of the running executable, which Go 1.23 and newer
+	// compilers emit; they are reported as unavailable when the binary is
+	// stripped (for example when built with -ldflags=-w, or by go run and go
+	// test) or was built by an older release. Captured values are not shown.
+
+	// Example: show captured variables
+	// Default: disabled
+	retries := 3
+	handler := func() int { return retries }
+	d := godump.NewDumper(godump.WithClosureCaptures())
+	d.Dump(handler)
+	// main.main.func1 (closure) main.go:9 #func() int {
+	//   retries => #int
+	// }
+}
@@ -0,0 +1,22 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithCompact prints slices, maps and structs on a single line when their
+	// rendering, including indentation, fits within maxWidth columns. Wider
+	// values keep the multi-line layout. A maxWidth of 0 disables compact output.
+
+	// Example: inline small values
+	// Default: 0 (disabled)
+	type Point struct {
+		X, Y int
+	}
+	d := godump.NewDumper(godump.WithCompact(80))
+	d.Dump([]int{1, 2, 3}, Point{X: 1, Y: 2})
+	// #[]int [1, 2, 3]
+	// #godump.Point {X: 1, Y: 2}
+}
@@ -10,7 +10,7 @@ import (
 
 func main() {
 	// WithDisableStringer disables using the fmt.Stringer output.
-	// When enabled, the underlying type is rendered instead of String().
+	// When enabled, the underlying type is rendered instead of String() or Error().
 
 	// Example: show raw types
 	// Default: false
@@ -0,0 +1,18 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithGoroutineID adds the id of the calling goroutine to the dump and diff
+	// headers, so output from concurrent workers can be told apart.
+
+	// Example: tag dumps with the goroutine id
+	// Default: disabled
+	d := godump.NewDumper(godump.WithGoroutineID())
+	d.Dump("job started")
+	// <#dump // main.go:12 [goroutine 1]
+	// "job started" #string
+}
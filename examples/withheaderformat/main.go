@@ -0,0 +1,22 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithHeaderFormat sets the header text from a text/template executed with a
+	// [HeaderInfo]. Headers that start with {{.File}}:{{.Line}} can still be read
+	// by [Parse]. A template that fails to parse or execute prints the error in
+	// place of the header.
+
+	// Example: add the function, time and elapsed time to headers
+	// Default: "{{.File}}:{{.Line}}"
+	d := godump.NewDumper(godump.WithHeaderFormat(
+		`{{.File}}:{{.Line}} {{.Function}} at {{.Time.Format "15:04:05.000"}} (+{{.Elapsed}})`,
+	))
+	d.Dump("job started")
+	// <#dump // main.go:14 main.main at 09:41:07.512 (+0s)
+	// "job started" #string
+}
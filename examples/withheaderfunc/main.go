@@ -0,0 +1,22 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"github.com/goforj/godump"
+)
+
+func main() {
+	// WithHeaderFunc sets a function that returns the header text from a
+	// [HeaderInfo], for headers a template cannot express.
+
+	// Example: tag headers with the host and process
+	d := godump.NewDumper(godump.WithHeaderFunc(func(h godump.HeaderInfo) string {
+		return fmt.Sprintf("%s:%d %s/%d", h.File, h.Line, h.Hostname, h.PID)
+	}))
+	d.Dump("ready")
+	// <#dump // main.go:15 web-1/4211
+	// "ready" #string
+}
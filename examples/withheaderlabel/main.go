@@ -0,0 +1,17 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithHeaderLabel adds a label to the dump and diff headers, such as the
+	// name of the component dumping. Header formats show it with {{.Label}}.
+
+	// Example: label the dumps of a worker
+	d := godump.NewDumper(godump.WithHeaderLabel("billing"))
+	d.Dump("charged")
+	// <#dump // main.go:12 billing
+	// "charged" #string
+}
@@ -0,0 +1,28 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"github.com/goforj/godump"
+)
+
+func main() {
+	// WithHTMLTheme makes DumpHTML, DiffHTML and DumpHTMLPage mark text with
+	// semantic CSS classes such as gd-type, gd-key, gd-string, gd-ref,
+	// gd-redacted and gd-diff-del instead of inline colors, inside a
+	// <pre class="godump"> block. stylesheet is included in a <style> element
+	// before the output; pass HTMLStylesheet() for the default look, or an empty
+	// string when the page styles the classes itself.
+
+	// Example: style HTML dumps from your own stylesheet
+	d := godump.NewDumper(godump.WithoutHeader(), godump.WithHTMLTheme(""))
+	html := d.DumpHTML(map[string]int{"a": 1})
+	fmt.Println(html)
+	// <pre class="godump">
+	// <span class="gd-type">#map[string]int</span> {
+	//    <span class="gd-key">a</span> => <span class="gd-number">1</span><span class="gd-type"> #int</span>
+	// }
+	// </pre>
+}
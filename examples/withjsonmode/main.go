@@ -0,0 +1,25 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithJSONMode sets the document shape used by DumpJSON and DumpJSONStr.
+
+	// Example: typed JSON
+	// Default: JSONPlain
+	type User struct{ Name string }
+	d := godump.NewDumper(godump.WithJSONMode(godump.JSONTyped))
+	d.DumpJSON(&User{Name: "Alice"})
+	// {
+	//   "type": "*main.User",
+	//   "fields": {
+	//     "Name": {
+	//       "type": "string",
+	//       "value": "Alice"
+	//     }
+	//   }
+	// }
+}
@@ -0,0 +1,26 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"github.com/goforj/godump"
+	"reflect"
+)
+
+func main() {
+	// WithMapKeyComparator orders map entries using a caller-supplied comparator.
+	// It implies MapKeyOrderCustom; a nil comparator falls back to sorted order.
+
+	// Example: order map entries in reverse
+	// Default: none
+	v := map[int]string{1: "a", 2: "b"}
+	d := godump.NewDumper(godump.WithMapKeyComparator(func(a, b reflect.Value) int {
+		return int(b.Int() - a.Int())
+	}))
+	d.Dump(v)
+	// #map[int]string {
+	//   2 => "b" #string
+	//   1 => "a" #string
+	// }
+}
@@ -0,0 +1,20 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithMapKeyOrder sets the order in which map entries are printed.
+
+	// Example: order map entries by key hash
+	// Default: MapKeyOrderSorted
+	v := map[string]int{"b": 1, "c": 2}
+	d := godump.NewDumper(godump.WithMapKeyOrder(godump.MapKeyOrderHash))
+	d.Dump(v)
+	// #map[string]int {
+	//   c => 2 #int
+	//   b => 1 #int
+	// }
+}
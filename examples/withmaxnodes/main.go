@@ -0,0 +1,23 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithMaxNodes stops a dump after n values have been visited, so a wide
+	// graph cannot produce unbounded output. Containers cut short show
+	// "... (truncated)" and the dump ends with a budget exceeded marker.
+	// Zero means no limit, the default.
+
+	// Example: bound the work of a dump in a hot path
+	d := godump.NewDumper(godump.WithMaxNodes(3))
+	d.Dump([]int{1, 2, 3, 4})
+	// #[]int [
+	//   0 => 1 #int
+	//   1 => 2 #int
+	//   ... (truncated)
+	// ]
+	// ... (budget exceeded: 3 nodes, 57 bytes)
+}
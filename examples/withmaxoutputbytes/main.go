@@ -0,0 +1,21 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithMaxOutputBytes stops a dump once its output would exceed n bytes. Only
+	// whole lines are written, followed by a budget exceeded marker. Every value
+	// prints at least one byte, so n also caps the values visited as WithMaxNodes
+	// does, which bounds JSON output and Inspect too. Zero means no limit, the
+	// default.
+
+	// Example: cap the size of dumps sent to logs
+	d := godump.NewDumper(godump.WithMaxOutputBytes(64 << 10))
+	d.Dump(map[string]int{"a": 1})
+	// #map[string]int {
+	//   a => 1 #int
+	// }
+}
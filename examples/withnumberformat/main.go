@@ -0,0 +1,19 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithNumberFormat sets how numbers are printed.
+
+	// Example: print integers as hex with separators
+	// Default: decimal, no separators
+	d := godump.NewDumper(godump.WithNumberFormat(godump.NumberFormat{
+		IntBase:   godump.IntBaseHex,
+		Separator: "_",
+	}))
+	d.Dump(3735928559)
+	// 0xdead_beef #int
+}
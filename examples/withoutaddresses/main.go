@@ -0,0 +1,19 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithoutAddresses turns off address annotations enabled by [WithAddresses],
+	// for example to keep test output deterministic with a shared set of options.
+
+	// Example: hide addresses
+	// Default: addresses hidden
+	opts := []godump.Option{godump.WithAddresses()}
+	d := godump.NewDumper(append(opts, godump.WithoutAddresses())...)
+	d.Dump(&struct{}{})
+	// #*struct {} {
+	// }
+}
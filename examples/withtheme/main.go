@@ -0,0 +1,20 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithTheme sets the colors of terminal, HTML and diff output. The default is
+	// DarkTheme; LightTheme, HighContrastTheme and MonochromeTheme are built in,
+	// and any other Theme can be passed. The dumper keeps a copy of t, so later
+	// changes to its Styles do not affect it.
+
+	// Example: readable output on a light terminal
+	d := godump.NewDumper(godump.WithTheme(godump.LightTheme()))
+	d.Dump(map[string]int{"a": 1})
+	// #map[string]int {
+	//   a => 1 #int
+	// }
+}
@@ -0,0 +1,22 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"github.com/goforj/godump"
+	"time"
+)
+
+func main() {
+	// WithTimeout stops inspecting values once a dump has taken longer than t,
+	// printing what was inspected so far and a budget exceeded marker. Zero
+	// means no limit, the default.
+
+	// Example: never spend more than a few milliseconds dumping
+	d := godump.NewDumper(godump.WithTimeout(5 * time.Millisecond))
+	d.Dump(map[string]int{"a": 1})
+	// #map[string]int {
+	//   a => 1 #int
+	// }
+}
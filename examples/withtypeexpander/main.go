@@ -0,0 +1,34 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"github.com/goforj/godump"
+	"reflect"
+)
+
+func main() {
+	// WithTypeExpander renders values of type t as the labeled children returned by fn.
+	// When t is an interface type, every implementer of t is expanded with fn.
+
+	// Example: expand a linked list
+	// Default: none
+	type Node struct {
+		Val  int
+		Next *Node
+	}
+	d := godump.NewDumper(godump.WithTypeExpander(reflect.TypeOf(Node{}), func(v reflect.Value) []godump.Child {
+		var out []godump.Child
+		for n := v.Addr().Interface().(*Node); n != nil; n = n.Next {
+			out = append(out, godump.Child{Label: fmt.Sprint(len(out)), Value: n.Val})
+		}
+		return out
+	}))
+	d.Dump(Node{Val: 1, Next: &Node{Val: 2}})
+	// #main.Node {
+	//   0 => 1 #int
+	//   1 => 2 #int
+	// }
+}
@@ -0,0 +1,24 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"fmt"
+	"github.com/goforj/godump"
+	"reflect"
+)
+
+func main() {
+	// WithTypeFormatter renders values of type t with fn instead of the default output.
+	// When t is an interface type, every implementer of t is formatted with fn.
+
+	// Example: format a type you don't own
+	// Default: none
+	type Cents int64
+	d := godump.NewDumper(godump.WithTypeFormatter(reflect.TypeOf(Cents(0)), func(v reflect.Value) string {
+		return fmt.Sprintf("$%d.%02d", v.Int()/100, v.Int()%100)
+	}))
+	d.Dump(Cents(1999))
+	// $19.99 #main.Cents
+}
@@ -0,0 +1,17 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "github.com/goforj/godump"
+
+func main() {
+	// WithUnicodeEscapes prints bidi controls and invisible characters as \u
+	// escapes instead of the default ‹U+XXXX› markers.
+
+	// Example: escape invisible characters
+	// Default: ‹U+202E› markers
+	d := godump.NewDumper(godump.WithUnicodeEscapes())
+	d.Dump("admin\u202egnp.exe")
+	// "admin\u202egnp.exe" #string
+}
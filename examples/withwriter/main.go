@@ -9,7 +9,9 @@ import (
 )
 
 func main() {
-	// WithWriter routes output to the provided writer.
+	// WithWriter routes output to the provided writer. Output is colored when
+	// the writer is a terminal, with the colors its TERM and COLORTERM support;
+	// FORCE_COLOR colors output to any writer and NO_COLOR turns colors off.
 
 	// Example: write to buffer
 	// Default: stdout
@@ -0,0 +1,120 @@
+package godump
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tagName is the struct tag key read by the dumper.
+const tagName = "godump"
+
+// fieldTag holds the options declared in a `godump:"..."` struct tag.
+//
+// Options are comma separated:
+//
+//	godump:"-"          omit the field
+//	godump:"redact"     always redact the field
+//	godump:"name=alias" print the field under another name
+//	godump:"hex"        print integers in the field as hexadecimal
+//	godump:"maxlen=N"   truncate strings in the field after N runes
+//	godump:"depth=N"    limit nesting below the field as WithMaxDepth(N) would
+type fieldTag struct {
+	omit   bool
+	redact bool
+	name   string
+	hex    bool
+	maxLen int
+	depth  int
+}
+
+// parseFieldTag reads the godump tag of a struct field. Unknown options and
+// malformed values are ignored.
+func parseFieldTag(field reflect.StructField) fieldTag {
+	tag := fieldTag{depth: -1}
+	raw, ok := field.Tag.Lookup(tagName)
+	if !ok {
+		return tag
+	}
+	if strings.TrimSpace(raw) == "-" {
+		tag.omit = true
+		return tag
+	}
+
+	for _, opt := range strings.Split(raw, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "redact":
+			tag.redact = true
+		case "name":
+			tag.name = value
+		case "hex":
+			tag.hex = true
+		case "maxlen":
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				tag.maxLen = n
+			}
+		case "depth":
+			if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+				tag.depth = n
+			}
+		}
+	}
+	return tag
+}
+
+// displayName returns the label printed for the field.
+func (t fieldTag) displayName(field reflect.StructField) string {
+	if t.name != "" {
+		return t.name
+	}
+	return field.Name
+}
+
+// includeField reports whether a struct field is printed, combining its tag
+// with the WithOnlyFields and WithExcludeFields filters. The filters match
+// either the Go field name or the tag alias.
+func (d *Dumper) includeField(field reflect.StructField, tag fieldTag) bool {
+	if tag.omit {
+		return false
+	}
+	if tag.name == "" {
+		return d.shouldIncludeField(field.Name)
+	}
+	if len(d.includeFields) > 0 &&
+		!d.matchesAny(field.Name, d.includeFields, FieldMatchExact) &&
+		!d.matchesAny(tag.name, d.includeFields, FieldMatchExact) {
+		return false
+	}
+	return !d.matchesAny(field.Name, d.excludeFields, d.fieldMatchMode) &&
+		!d.matchesAny(tag.name, d.excludeFields, d.fieldMatchMode)
+}
+
+// redactField reports whether a struct field is redacted by its tag or by the
+// WithRedactFields options.
+func (d *Dumper) redactField(field reflect.StructField, tag fieldTag) bool {
+	if tag.redact || d.shouldRedactField(field.Name) {
+		return true
+	}
+	return tag.name != "" && d.shouldRedactField(tag.name)
+}
+
+// fieldDumper returns the dumper used for a field's value, applying the tag's
+// hex, maxlen and depth options. indent is the level the value is printed at.
+func (d *Dumper) fieldDumper(tag fieldTag, indent int) *Dumper {
+	if !tag.hex && tag.maxLen == 0 && tag.depth < 0 {
+		return d
+	}
+
+	sub := d.clone()
+	if tag.hex {
+		sub.numberFormat.IntBase = IntBaseHex
+	}
+	if tag.maxLen > 0 && tag.maxLen < sub.maxStringLen {
+		sub.maxStringLen = tag.maxLen
+	}
+	if tag.depth >= 0 && indent+tag.depth < sub.maxDepth {
+		sub.maxDepth = indent + tag.depth
+	}
+	return sub
+}
@@ -0,0 +1,92 @@
+package godump
+
+import (
+	"reflect"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+func TestParseFieldTag(t *testing.T) {
+	type tagged struct {
+		Plain   int
+		Omitted int `godump:"-"`
+		All     int `godump:"redact, name=alias,hex,maxlen=5,depth=0"`
+		Invalid int `godump:"maxlen=x,depth=-1,unknown"`
+	}
+	typ := reflect.TypeOf(tagged{})
+
+	assert.Equal(t, fieldTag{depth: -1}, parseFieldTag(typ.Field(0)))
+	assert.Equal(t, fieldTag{omit: true, depth: -1}, parseFieldTag(typ.Field(1)))
+	assert.Equal(t, fieldTag{redact: true, name: "alias", hex: true, maxLen: 5, depth: 0}, parseFieldTag(typ.Field(2)))
+	assert.Equal(t, fieldTag{depth: -1}, parseFieldTag(typ.Field(3)))
+}
+
+func TestFieldTagsInDump(t *testing.T) {
+	type Org struct {
+		Name    string
+		Members []string
+	}
+	type User struct {
+		ID       int    `godump:"hex"`
+		Token    string `godump:"redact"`
+		Bio      string `godump:"maxlen=5"`
+		Internal string `godump:"-"`
+		Org      Org    `godump:"name=organization,depth=1"`
+	}
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(User{ID: 255, Token: "secret", Bio: "long biography", Internal: "x", Org: Org{Name: "acme", Members: []string{"alice"}}})
+	assert.Contains(t, out, "+ID           => 0xff #int")
+	assert.Contains(t, out, "+Token        => <redacted> #string")
+	assert.Contains(t, out, `+Bio          => "long …" #string`)
+	assert.NotContains(t, out, "Internal")
+	assert.Contains(t, out, "+organization => #godump.Org {")
+	assert.Contains(t, out, `"acme" #string`)
+	assert.Contains(t, out, "+Members => ... (max depth)")
+}
+
+func TestFieldTagsComposeWithOptions(t *testing.T) {
+	type Page struct {
+		Token string
+	}
+	type User struct {
+		Token string `godump:"redact"`
+		Email string `godump:"name=mail"`
+		Page  Page
+	}
+	v := User{Token: "user-secret", Email: "a@b.c", Page: Page{Token: "next-page"}}
+
+	// The tag redacts User.Token only; Page.Token is untouched.
+	out := newDumperT(t, WithoutHeader()).DumpStr(v)
+	assert.Contains(t, out, "<redacted>")
+	assert.Contains(t, out, `"next-page"`)
+
+	out = newDumperT(t, WithoutHeader(), WithExcludeFields("mail")).DumpStr(v)
+	assert.NotContains(t, out, "a@b.c")
+
+	out = newDumperT(t, WithoutHeader(), WithOnlyFields("Email")).DumpStr(v)
+	assert.Contains(t, out, `+mail => "a@b.c" #string`)
+	assert.NotContains(t, out, "Token")
+
+	out = newDumperT(t, WithoutHeader(), WithRedactFields("mail")).DumpStr(v)
+	assert.Contains(t, out, "+mail  => <redacted> #string")
+}
+
+func TestFieldTagsInDiffAndGo(t *testing.T) {
+	type Session struct {
+		User  string
+		Token string `godump:"redact"`
+		Cache []int  `godump:"-"`
+	}
+	a := Session{User: "alice", Token: "one", Cache: []int{1}}
+	b := Session{User: "alice", Token: "two", Cache: []int{2}}
+
+	out := newDumperT(t, WithoutColor()).DiffStr(a, b)
+	assert.NotContains(t, out, "one")
+	assert.NotContains(t, out, "two")
+	assert.NotContains(t, out, "Cache")
+
+	lit := newDumperT(t).DumpGoStr(a)
+	assert.Contains(t, lit, "// Token: <redacted>")
+	assert.NotContains(t, lit, "Cache")
+}
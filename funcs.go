@@ -0,0 +1,241 @@
+package godump
+
+import (
+	"debug/dwarf"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WithClosureCaptures lists the names and types of the variables captured by
+// closures below the function name. Captures are located through the DWARF
+// debug information of the running executable, which Go 1.23 and newer
+// compilers emit; they are reported as unavailable when the binary is
+// stripped (for example when built with -ldflags=-w, or by go run and go
+// test) or was built by an older release. Captured values are not shown.
+// @group Options
+//
+// Example: show captured variables
+//
+//	// Default: disabled
+//	retries := 3
+//	handler := func() int { return retries }
+//	d := godump.NewDumper(godump.WithClosureCaptures())
+//	d.Dump(handler)
+//	// main.main.func1 (closure) main.go:9 #func() int {
+//	//   retries => #int
+//	// }
+func WithClosureCaptures() Option {
+	return func(d *Dumper) *Dumper {
+		d.showClosureCaptures = true
+		return d
+	}
+}
+
+// printFunc renders a function node as its qualified name, whether it is a
+// closure or method value, where it is defined and its captures.
+func (d *Dumper) printFunc(w io.Writer, n *Node, indent int) {
+	if n.Value == "" {
+		fmt.Fprint(w, d.colorize(RoleType, strings.TrimLeft(n.Type, "*")+" #"+n.Type))
+		return
+	}
+
+	fmt.Fprint(w, d.colorize(RoleText, d.sanitize(n.Value)))
+	if n.Note != "" {
+		fmt.Fprint(w, d.colorize(RoleMuted, " ("+n.Note+")"))
+	}
+	if n.Location != "" {
+		fmt.Fprint(w, d.colorize(RoleMeta, " "+d.sanitize(n.Location)))
+	}
+	fmt.Fprint(w, " ")
+
+	switch {
+	case n.noCaptures:
+		fmt.Fprint(w, d.colorize(RoleType, "#"+n.Type)+d.colorize(RoleMuted, " (captures unavailable)"))
+	case n.MaxDepth:
+		fmt.Fprint(w, d.colorize(RoleType, "#"+n.Type)+d.colorize(RoleMuted, " ... (max depth)"))
+	case len(n.Children) > 0:
+		d.printBlock(w, n, indent)
+	default:
+		fmt.Fprint(w, d.colorize(RoleType, "#"+n.Type))
+	}
+}
+
+// funcKind strips the method value suffix from a runtime function name and
+// reports whether it names a closure or a method value.
+func funcKind(name string) (string, string) {
+	if trimmed := strings.TrimSuffix(name, "-fm"); trimmed != name {
+		return trimmed, "method value"
+	}
+
+	// Closures are named after their enclosing function with a funcN element,
+	// such as main.main.func1 or main.main.func1.2 for nested literals.
+	local := name[strings.LastIndex(name, "/")+1:]
+	for _, part := range strings.Split(local, ".")[1:] {
+		if strings.HasPrefix(part, "func") && len(part) > 4 && isDigits(part[4:]) {
+			return name, "closure"
+		}
+	}
+	return name, ""
+}
+
+// isDigits reports whether s consists of ASCII digits only.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// capturedVar is a variable captured by a closure, as recorded in DWARF.
+type capturedVar struct {
+	name   string
+	typ    string
+	offset int64
+}
+
+// closureIndex holds the captured variables of every closure in the running
+// executable, in closure layout order.
+type closureIndex struct {
+	funcs map[string][]capturedVar
+}
+
+// DWARF attributes emitted by the Go toolchain.
+const dwAttrGoClosureOffset dwarf.Attr = 0x2907
+
+var (
+	closureIndexOnce sync.Once
+	closureIndexVal  *closureIndex
+	closureIndexErr  error
+)
+
+// loadClosureIndex reads the closure layouts of the running executable once.
+func loadClosureIndex() (*closureIndex, error) {
+	closureIndexOnce.Do(func() {
+		exe, err := os.Executable()
+		if err != nil {
+			closureIndexErr = err
+			return
+		}
+		data, err := openDWARF(exe)
+		if err != nil {
+			closureIndexErr = err
+			return
+		}
+		closureIndexVal, closureIndexErr = buildClosureIndex(data)
+	})
+	return closureIndexVal, closureIndexErr
+}
+
+// openDWARF loads the debug information of an ELF, Mach-O or PE executable.
+func openDWARF(path string) (*dwarf.Data, error) {
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		return f.DWARF()
+	}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		return f.DWARF()
+	}
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		return f.DWARF()
+	}
+	return nil, errors.New("unsupported executable format")
+}
+
+// buildClosureIndex collects every variable with a closure offset, grouped
+// by the function it belongs to.
+func buildClosureIndex(data *dwarf.Data) (*closureIndex, error) {
+	idx := &closureIndex{funcs: map[string][]capturedVar{}}
+	types, origins := data.Reader(), data.Reader()
+
+	var current string
+	r := data.Reader()
+	for {
+		e, err := r.Next()
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			break
+		}
+
+		switch e.Tag {
+		case dwarf.TagCompileUnit:
+			current = ""
+		case dwarf.TagSubprogram:
+			current, _ = e.Val(dwarf.AttrName).(string)
+			// Out-of-line copies of inlinable functions take their name from
+			// the abstract entry they refer to.
+			if origin, ok := e.Val(dwarf.AttrAbstractOrigin).(dwarf.Offset); ok && current == "" {
+				origins.Seek(origin)
+				if oe, err := origins.Next(); err == nil && oe != nil {
+					current, _ = oe.Val(dwarf.AttrName).(string)
+				}
+			}
+			if _, seen := idx.funcs[current]; current != "" && !seen {
+				idx.funcs[current] = nil
+			}
+		case dwarf.TagVariable:
+			name, _ := e.Val(dwarf.AttrName).(string)
+			offset, ok := e.Val(dwAttrGoClosureOffset).(int64)
+			if !ok || current == "" {
+				continue
+			}
+			// A variable named &x is stored as a pointer to x, either because
+			// it is captured by reference or because the closure moved its
+			// copy to the heap. Either way x itself has the element type.
+			typ := dwarfTypeName(types, e)
+			if trimmed := strings.TrimPrefix(name, "&"); trimmed != name {
+				name, typ = trimmed, strings.TrimPrefix(typ, "*")
+			}
+			idx.funcs[current] = append(idx.funcs[current], capturedVar{name: name, typ: typ, offset: offset})
+		}
+	}
+	for _, vars := range idx.funcs {
+		sort.Slice(vars, func(i, j int) bool { return vars[i].offset < vars[j].offset })
+	}
+	return idx, nil
+}
+
+// dwarfTypeName returns the Go name of the type of entry e, or "" when it has
+// none.
+func dwarfTypeName(types *dwarf.Reader, e *dwarf.Entry) string {
+	off, ok := e.Val(dwarf.AttrType).(dwarf.Offset)
+	if !ok {
+		return ""
+	}
+	types.Seek(off)
+	te, err := types.Next()
+	if err != nil || te == nil {
+		return ""
+	}
+	name, _ := te.Val(dwarf.AttrName).(string)
+	return name
+}
+
+// closureCaptures lists the variables captured by the closure fn. Only their
+// names and types are known: the closure's memory is never read, since its
+// layout is private to the compiler.
+func closureCaptures(fn *runtime.Func) ([]capturedVar, error) {
+	idx, err := loadClosureIndex()
+	if err != nil {
+		return nil, err
+	}
+	vars, ok := idx.funcs[fn.Name()]
+	if !ok {
+		return nil, errors.New("closure not found in debug information")
+	}
+	return vars, nil
+}
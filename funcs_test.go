@@ -0,0 +1,152 @@
+package godump
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+func namedFuncForTest() int { return 1 }
+
+type funcReceiver struct{ n int }
+
+func (r funcReceiver) Get() int { return r.n }
+
+func TestFuncKind(t *testing.T) {
+	tests := []struct {
+		in, name, kind string
+	}{
+		{"main.main.func1", "main.main.func1", "closure"},
+		{"main.main.func1.2", "main.main.func1.2", "closure"},
+		{"example.com/pkg.glob..func3", "example.com/pkg.glob..func3", "closure"},
+		{"example.com/pkg.T.Get-fm", "example.com/pkg.T.Get", "method value"},
+		{"strings.ToUpper", "strings.ToUpper", ""},
+		{"example.com/func1.Run", "example.com/func1.Run", ""},
+		{"main.functional", "main.functional", ""},
+	}
+	for _, tt := range tests {
+		name, kind := funcKind(tt.in)
+		assert.Equal(t, tt.name, name)
+		assert.Equal(t, tt.kind, kind)
+	}
+}
+
+func TestFuncNameAndLocation(t *testing.T) {
+	d := newDumperT(t, WithoutHeader())
+
+	_, file, line, _ := runtime.Caller(0)
+	closure := func() int { return line }
+	loc := relativePath(file) + ":" + strconv.Itoa(line+1)
+
+	assert.Equal(t, "github.com/goforj/godump.TestFuncNameAndLocation.func1 (closure) "+loc+" #func() int\n", d.DumpStr(closure))
+
+	out := d.DumpStr(namedFuncForTest)
+	assert.Contains(t, out, "github.com/goforj/godump.namedFuncForTest funcs_test.go:")
+	assert.NotContains(t, out, "(closure)")
+
+	out = d.DumpStr(funcReceiver{n: 1}.Get)
+	assert.Equal(t, "github.com/goforj/godump.funcReceiver.Get (method value) #func() int\n", out)
+
+	fn := namedFuncForTest
+	assert.Contains(t, d.DumpStr(&fn), "godump.namedFuncForTest funcs_test.go:")
+	assert.Contains(t, d.DumpStr(&fn), " #*func() int\n")
+
+	var nilFn func()
+	assert.Equal(t, "func()(nil)\n", d.DumpStr(nilFn))
+}
+
+func TestClosureCapturesUnavailable(t *testing.T) {
+	if _, err := loadClosureIndex(); err == nil {
+		t.Skip("test binary has closure debug information")
+	}
+	n := 1
+	closure := func() int { return n }
+	out := newDumperT(t, WithoutHeader(), WithClosureCaptures()).DumpStr(closure)
+	assert.Contains(t, out, "(closure)")
+	assert.Contains(t, out, "#func() int (captures unavailable)\n")
+
+	// Plain functions have nothing to capture.
+	out = newDumperT(t, WithoutHeader(), WithClosureCaptures()).DumpStr(namedFuncForTest)
+	assert.NotContains(t, out, "captures")
+}
+
+// Test binaries carry no DWARF, so captures are checked in a program built
+// with go build.
+const closureCapturesProgram = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/goforj/godump"
+)
+
+type point struct{ X, Y int }
+
+func main() {
+	label := "jobs"
+	p := point{X: 1, Y: 2}
+	count := 0
+	big := [20]int{7}
+	inc := func() int { count++; return count }
+	inc()
+	show := func() { fmt.Println(label, p, big[0]) }
+	d := godump.NewDumper(godump.WithWriter(os.Stdout), godump.WithoutHeader(), godump.WithoutColor(), godump.WithClosureCaptures())
+	d.Dump(inc, show)
+}
+`
+
+func TestClosureCapturesFromDebugInfo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a helper program")
+	}
+	if v, err := strconv.Atoi(strings.SplitN(strings.TrimPrefix(runtime.Version(), "go1."), ".", 2)[0]); err == nil && v < 23 {
+		t.Skip("closure offsets need Go 1.23 or newer")
+	}
+	goBin := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(goBin); err != nil {
+		t.Skip("go command not available")
+	}
+	root, err := os.Getwd()
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	gomod := "module captures\n\ngo 1.18\n\nrequire github.com/goforj/godump v0.0.0\n\nreplace github.com/goforj/godump => " + strconv.Quote(root) + "\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(closureCapturesProgram), 0o644))
+
+	exe := filepath.Join(dir, "captures")
+	build := exec.Command(goBin, "build", "-o", exe, ".")
+	build.Dir = dir
+	build.Env = append(os.Environ(), "GOFLAGS=", "GOTOOLCHAIN=local")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	run := exec.Command(exe)
+	run.Dir = dir
+	out, err := run.CombinedOutput()
+	assert.NoError(t, err)
+
+	got := string(out)
+	// Captures are listed by name and type, whether captured by value or by
+	// reference.
+	assert.Contains(t, got, "main.main.func1 (closure) main.go:17 #func() int {\n  count => #int\n}\n")
+	if strconv.IntSize == 32 {
+		// 32-bit compilers leave func2's captures out of the debug information.
+		return
+	}
+	assert.Contains(t, got, "main.main.func2 (closure) main.go:19 #func() {\n")
+	assert.Contains(t, got, "  p   => #main.point\n  big => #[20]int\n}\n")
+
+	dumps, err := Parse(got)
+	assert.NoError(t, err)
+	count := dumps[0].Values[0].Children[0]
+	assert.Equal(t, "count", count.Name)
+	assert.Equal(t, "int", count.Type)
+}
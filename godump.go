@@ -1,15 +1,18 @@
 package godump
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
-	"text/tabwriter"
+	"time"
 	"unicode/utf8"
 	"unsafe"
 )
@@ -28,7 +31,10 @@ const (
 	colorRef     = "\033[38;5;247m"
 	colorMeta    = "\033[38;5;170m"
 	colorDefault = "\033[38;5;208m"
-	indentWidth  = 2
+	// colorPlain marks text printed without color. It is still passed
+	// through the colorizer so HTML output can escape it.
+	colorPlain  = ""
+	indentWidth = 2
 )
 
 // Default configuration values for the Dumper.
@@ -95,49 +101,93 @@ func colorizeUnstyled(code, str string) string {
 	return str // No colorization
 }
 
-// colorizeANSI colorizes the string using ANSI escape codes.
+// colorizeANSI colorizes the string using ANSI escape codes from DarkTheme.
 //
 // It satisfies the [Colorizer] interface.
 func colorizeANSI(code, str string) string {
-	return code + str + colorReset
+	return darkTheme.colorizeANSI(code, str)
 }
 
-// htmlColorMap maps color codes to HTML colors.
-var htmlColorMap = map[string]string{
-	colorGray:    "#999",
-	colorYellow:  "#ffb400",
-	colorRed:     "#ff5f5f",
-	colorGreen:   "#55d655",
-	colorLime:    "#80ff80",
-	colorNote:    "#40c0ff",
-	colorRef:     "#aaa",
-	colorMeta:    "#d087d0",
-	colorDefault: "#ff7f00",
+// colorizeHTML HTML-escapes the string and colorizes it using span tags with
+// the colors of DarkTheme.
+//
+// It satisfies the [Colorizer] interface.
+func colorizeHTML(code, str string) string {
+	return darkTheme.colorizeHTML(code, str)
 }
 
-// colorizeHTML colorizes the string using HTML span tags.
+// colorizeHTMLUnstyled HTML-escapes the string without colorizing it.
 //
 // It satisfies the [Colorizer] interface.
-func colorizeHTML(code, str string) string {
-	return fmt.Sprintf(`<span style="color:%s">%s</span>`, htmlColorMap[code], str)
+func colorizeHTMLUnstyled(code, str string) string {
+	return html.EscapeString(str)
+}
+
+// htmlColorizer returns the colorizer for HTML output.
+func (d *Dumper) htmlColorizer() Colorizer {
+	switch {
+	case d.disableColor:
+		return colorizeHTMLUnstyled
+	case d.htmlClasses:
+		return colorizeHTMLClasses
+	default:
+		return d.currentTheme().colorizeHTML
+	}
 }
 
 // Dumper holds configuration for dumping structured data.
 // It controls depth, item count, and string length limits.
 type Dumper struct {
-	maxDepth           int
-	maxItems           int
-	maxStringLen       int
-	writer             io.Writer
-	skippedStackFrames int
-	disableStringer    bool
-	disableColor       bool
-	disableHeader      bool
-	includeFields      []string
-	excludeFields      []string
-	redactFields       []string
-	fieldMatchMode     FieldMatchMode
-	redactMatchMode    FieldMatchMode
+	maxDepth            int
+	maxItems            int
+	maxStringLen        int
+	writer              io.Writer
+	skippedStackFrames  int
+	disableStringer     bool
+	disableColor        bool
+	disableHeader       bool
+	includeFields       []string
+	excludeFields       []string
+	redactFields        []string
+	fieldMatchMode      FieldMatchMode
+	redactMatchMode     FieldMatchMode
+	mapKeyOrder         MapKeyOrder
+	mapKeyComparator    MapKeyComparator
+	typeHooks           []typeHook
+	numberFormat        NumberFormat
+	compactWidth        int
+	plans               *planCache
+	showGoroutineID     bool
+	showChanBuffers     bool
+	showClosureCaptures bool
+	showAddresses       bool
+	unicodeEscapes      bool
+	jsonMode            JSONMode
+	htmlClasses         bool
+	htmlStylesheet      string
+	// htmlOutput is set on the copies that render HTML.
+	htmlOutput bool
+	// jsonPlain is set on the copies that build plain JSON, which follow
+	// the encoding/json rules for fields and marshalers.
+	jsonPlain bool
+	// theme holds the styles of each role; nil means DarkTheme.
+	theme *Theme
+	// colorDepth is the color depth of the writer, detected with the colorizer.
+	colorDepth colorDepth
+	// maxNodes, maxOutputBytes and timeout bound the work of each dump, and
+	// ctx is the context of the dump in progress, if any.
+	maxNodes       int
+	maxOutputBytes int
+	timeout        time.Duration
+	ctx            context.Context
+	// headerFunc formats the header text, or nil for the default;
+	// headerGoroutine is set when it may read HeaderInfo.Goroutine, which is
+	// slow to look up. clock is shared by copies of the Dumper to time their
+	// headers.
+	headerFunc      HeaderFunc
+	headerGoroutine bool
+	headerLabel     string
+	clock           *headerClock
 
 	// callerFn is used to get the caller information.
 	// It defaults to [runtime.Caller], it is here to be overridden for testing purposes.
@@ -153,14 +203,26 @@ type Option func(*Dumper) *Dumper
 // dumpState tracks reference ids for a single dump call.
 type dumpState struct {
 	nextRefID int
-	refs      map[uintptr]int
+	// refs maps every printed reference to its id, or 0 when it has no anchor.
+	refs map[refKey]int
+	// shared holds the references reached more than once, found before printing.
+	shared map[refKey]bool
+	// regions and aliases track overlapping memory when addresses are shown.
+	regions []memRegion
+	aliases map[refKey]refKey
+	// budget bounds the values inspected; nil means no limits.
+	budget *outputBudget
+	// stream leaves the children of structs, maps and lists to be inspected
+	// as they are printed.
+	stream bool
 }
 
 // newDumpState initializes per-dump reference tracking.
 func newDumpState() *dumpState {
 	return &dumpState{
 		nextRefID: 1,
-		refs:      map[uintptr]int{},
+		refs:      map[refKey]int{},
+		shared:    map[refKey]bool{},
 	}
 }
 
@@ -232,7 +294,9 @@ func WithMaxStringLen(n int) Option {
 	}
 }
 
-// WithWriter routes output to the provided writer.
+// WithWriter routes output to the provided writer. Output is colored when
+// the writer is a terminal, with the colors its TERM and COLORTERM support;
+// FORCE_COLOR colors output to any writer and NO_COLOR turns colors off.
 // @group Options
 //
 // Example: write to buffer
@@ -276,7 +340,7 @@ func WithSkipStackFrames(n int) Option {
 }
 
 // WithDisableStringer disables using the fmt.Stringer output.
-// When enabled, the underlying type is rendered instead of String().
+// When enabled, the underlying type is rendered instead of String() or Error().
 // @group Options
 //
 // Example: show raw types
@@ -506,10 +570,13 @@ func NewDumper(opts ...Option) *Dumper {
 		callerFn:        runtime.Caller,
 		fieldMatchMode:  FieldMatchExact,
 		redactMatchMode: FieldMatchExact,
+		mapKeyOrder:     MapKeyOrderSorted,
 	}
 	for _, opt := range opts {
 		d = opt(d)
 	}
+	d.plans = &planCache{}
+	d.clock = &headerClock{}
 	return d
 }
 
@@ -527,7 +594,9 @@ func Dump(vs ...any) {
 	defaultDumper.Dump(vs...)
 }
 
-// Dump prints the values to stdout with colorized output.
+// Dump prints the values to the configured writer with colorized output.
+// Output is streamed as it is rendered instead of being buffered in full, and
+// concurrent dumps to the same writer are written one after another.
 // @group Dump
 //
 // Example: print with a custom dumper
@@ -539,7 +608,10 @@ func Dump(vs ...any) {
 //	//   a => 1 #int
 //	// }
 func (d *Dumper) Dump(vs ...any) {
-	fmt.Fprint(d.writer, d.DumpStr(vs...))
+	defer lockWriter(d.writer)()
+	bw := bufio.NewWriter(d.writer)
+	d.clone().writeDumpWithHeader(bw, vs...)
+	bw.Flush()
 }
 
 // Fdump writes the formatted dump of values to the given io.Writer.
@@ -579,17 +651,22 @@ func DumpStr(vs ...any) string {
 //	_ = out
 //	// "#map[string]int {\n  a => 1 #int\n}" #string
 func (d *Dumper) DumpStr(vs ...any) string {
-	local := d.clone()
-	state := newDumpState()
 	var sb strings.Builder
-	local.printDumpHeader(&sb)
-	tw := tabwriter.NewWriter(&sb, 0, 0, 1, ' ', 0)
-	local.writeDump(tw, state, vs...)
-	tw.Flush()
+	d.clone().writeDumpWithHeader(&sb, vs...)
 	return sb.String()
 }
 
-// DumpJSONStr pretty-prints values as JSON and returns it as a string.
+// writeDumpWithHeader streams the header and values to w. Output is written
+// as it is produced, so memory use is bounded by the nesting depth rather
+// than the size of the dump.
+func (d *Dumper) writeDumpWithHeader(w io.Writer, vs ...any) {
+	d.printDumpHeader(w)
+	d.writeDump(w, vs...)
+}
+
+// DumpJSONStr pretty-prints values as JSON and returns it as a string. Field
+// filters, redaction and limits apply as in text dumps, and the document
+// shape is set with [WithJSONMode].
 // @group JSON
 //
 // Example: dump JSON string
@@ -604,12 +681,7 @@ func (d *Dumper) DumpJSONStr(vs ...any) string {
 		return `{"error": "DumpJSON called with no arguments"}`
 	}
 
-	var data any = vs
-	if len(vs) == 1 {
-		data = vs[0]
-	}
-
-	b, err := json.MarshalIndent(data, "", strings.Repeat(" ", indentWidth))
+	b, err := d.jsonDocument(vs)
 	if err != nil {
 		//nolint:errchkjson // fallback handles this manually below
 		errorJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
@@ -631,10 +703,11 @@ func (d *Dumper) DumpJSONStr(vs ...any) string {
 //	// }
 func (d *Dumper) DumpJSON(vs ...any) {
 	output := d.DumpJSONStr(vs...)
-	fmt.Fprintln(d.writer, output)
+	writeLocked(d.writer, output+"\n")
 }
 
-// DumpHTML dumps the values as HTML with colorized output.
+// DumpHTML dumps the values as HTML with colorized output. All text is
+// HTML-escaped, so the result can be embedded in a page as-is.
 // @group HTML
 //
 // Example: dump HTML
@@ -647,7 +720,8 @@ func DumpHTML(vs ...any) string {
 	return defaultDumper.DumpHTML(vs...)
 }
 
-// DumpHTML dumps the values as HTML with colorized output.
+// DumpHTML dumps the values as HTML with colorized output. All text is
+// HTML-escaped, so the result can be embedded in a page as-is.
 // @group HTML
 //
 // Example: dump HTML with a custom dumper
@@ -660,22 +734,15 @@ func DumpHTML(vs ...any) string {
 //	// (html output)
 func (d *Dumper) DumpHTML(vs ...any) string {
 	var sb strings.Builder
-	sb.WriteString(`<div style='background-color:black;'><pre style="background-color:black; color:white; padding:5px; border-radius: 5px">` + "\n")
-
-	htmlDumper := d.clone()
-	if !htmlDumper.disableColor {
-		htmlDumper.colorizer = colorizeHTML // use HTML colorizer
-	}
-
-	sb.WriteString(htmlDumper.DumpStr(vs...))
-
-	sb.WriteString("</pre></div>")
+	sb.WriteString(d.htmlOpen())
+	sb.WriteString(d.htmlClone().DumpStr(vs...))
+	sb.WriteString(d.htmlClose())
 	return sb.String()
 }
 
 // DumpJSON dumps the values as a pretty-printed JSON string.
 // If there is more than one value, they are dumped as a JSON array.
-// It returns an error string if marshaling fails.
+// It prints an error object if encoding fails.
 // @group JSON
 //
 // Example: print JSON
@@ -740,27 +807,23 @@ func (d *Dumper) clone() *Dumper {
 }
 
 // colorize applies the configured [Colorizer] to the string with the given color code.
-func (d *Dumper) colorize(code, str string) string {
-	if d.colorizer == nil {
-		// this avoids detecting color if not needed
-		if d.disableColor {
-			d.colorizer = colorizeUnstyled
-			return d.colorizer(code, str)
-		}
-		d.colorizer = newColorizer()
-	}
-	return d.colorizer(code, str)
+func (d *Dumper) colorize(code Role, str string) string {
+	d.ensureColorizer()
+	return d.colorizer(string(code), str)
 }
 
-// ensureColorizer initializes the colorizer when none is configured.
+// ensureColorizer initializes the colorizer when none is configured. Colors
+// follow the terminal capabilities of the configured writer.
 func (d *Dumper) ensureColorizer() {
-	if d.colorizer == nil {
-		if d.disableColor {
-			d.colorizer = colorizeUnstyled
-			return
-		}
-		d.colorizer = newColorizer()
+	if d.colorizer != nil {
+		return
+	}
+	if d.disableColor {
+		d.colorizer = colorizeUnstyled
+		return
 	}
+	d.colorDepth = detectColorDepth(d.writer)
+	d.colorizer = d.currentTheme().ansiColorizer(d.colorDepth)
 }
 
 // printDumpHeader prints the header for the dump output, including the file and line number.
@@ -768,24 +831,32 @@ func (d *Dumper) printDumpHeader(out io.Writer) {
 	if d.disableHeader {
 		return
 	}
-	file, line := d.findFirstNonInternalFrame(d.skippedStackFrames)
+	file, line, pc := d.findCaller(d.skippedStackFrames)
 	if file == "" {
 		return
 	}
+	fmt.Fprintln(out, d.colorize(RoleHeader, "<#dump // "+d.headerText("dump", file, line, pc)))
+}
 
-	relPath := file
+// relativePath returns file relative to the working directory when possible.
+func relativePath(file string) string {
 	if wd, err := os.Getwd(); err == nil {
 		if rel, err := filepath.Rel(wd, file); err == nil {
-			relPath = rel
+			return rel
 		}
 	}
-
-	header := fmt.Sprintf("<#dump // %s:%d", relPath, line)
-	fmt.Fprintln(out, d.colorize(colorGray, header))
+	return file
 }
 
 // findFirstNonInternalFrame iterates through the call stack to find the first non-internal frame.
 func (d *Dumper) findFirstNonInternalFrame(skip int) (string, int) {
+	file, line, _ := d.findCaller(skip)
+	return file, line
+}
+
+// findCaller returns the file, line and program counter of the first
+// non-internal frame, after skipping skip more.
+func (d *Dumper) findCaller(skip int) (string, int, uintptr) {
 	for i := initialCallerSkip; i < defaultMaxStackDepth; i++ {
 		pc, file, line, ok := d.callerFn(i)
 		if !ok {
@@ -798,14 +869,15 @@ func (d *Dumper) findFirstNonInternalFrame(skip int) (string, int) {
 				continue
 			}
 
-			return file, line
+			return file, line, pc
 		}
 	}
-	return "", 0
+	return "", 0, 0
 }
 
-// formatByteSliceAsHexDump formats a byte slice as a hex dump with ASCII representation.
-func (d *Dumper) formatByteSliceAsHexDump(b []byte, indent int) string {
+// formatByteSliceAsHexDump formats a byte slice as a hex dump with ASCII
+// representation. The address tag, if any, follows the header.
+func (d *Dumper) formatByteSliceAsHexDump(b []byte, indent int, addrTag string) string {
 	var sb strings.Builder
 
 	const lineLen = 16
@@ -816,7 +888,7 @@ func (d *Dumper) formatByteSliceAsHexDump(b []byte, indent int) string {
 	bodyIndent := fieldIndent
 
 	// Header
-	sb.WriteString(fmt.Sprintf("([]uint8) (len=%d cap=%d) {\n", len(b), cap(b)))
+	sb.WriteString(d.colorize(RoleText, fmt.Sprintf("([]uint8) (len=%d cap=%d)", len(b), cap(b))) + addrTag + d.colorize(RoleText, " {") + "\n")
 
 	for i := 0; i < len(b); i += lineLen {
 
@@ -831,7 +903,7 @@ func (d *Dumper) formatByteSliceAsHexDump(b []byte, indent int) string {
 		// Offset
 		offsetStr := fmt.Sprintf("%08x  ", i)
 		sb.WriteString(bodyIndent)
-		sb.WriteString(d.colorize(colorMeta, offsetStr))
+		sb.WriteString(d.colorize(RoleMeta, offsetStr))
 		visibleLen += len(offsetStr)
 
 		// Hex bytes
@@ -845,7 +917,7 @@ func (d *Dumper) formatByteSliceAsHexDump(b []byte, indent int) string {
 			if j == 7 {
 				hexStr += " "
 			}
-			sb.WriteString(d.colorize(colorCyan, hexStr))
+			sb.WriteString(d.colorize(RoleNumber, hexStr))
 			visibleLen += len(hexStr)
 		}
 
@@ -857,37 +929,57 @@ func (d *Dumper) formatByteSliceAsHexDump(b []byte, indent int) string {
 		sb.WriteString(strings.Repeat(" ", padding))
 
 		// ASCII section
-		sb.WriteString(d.colorize(colorGray, "| "))
+		sb.WriteString(d.colorize(RoleMuted, "| "))
 		asciiCount := 0
 		for _, c := range line {
 			ch := "."
 			if c >= 32 && c <= 126 {
 				ch = string(c)
 			}
-			sb.WriteString(d.colorize(colorLime, ch))
+			sb.WriteString(d.colorize(RoleString, ch))
 			asciiCount++
 		}
 		if asciiCount < asciiMaxLen {
 			sb.WriteString(strings.Repeat(" ", asciiMaxLen-asciiCount))
 		}
-		sb.WriteString(d.colorize(colorGray, " |") + "\n")
+		sb.WriteString(d.colorize(RoleMuted, " |") + "\n")
 	}
 
 	// Closing
 	fieldIndent = fieldIndent[:len(fieldIndent)-indentWidth]
-	sb.WriteString(fieldIndent + "}")
+	sb.WriteString(fieldIndent + d.colorize(RoleText, "}"))
 	return sb.String()
 }
 
-func (d *Dumper) writeDump(w io.Writer, state *dumpState, vs ...any) {
-	for _, v := range vs {
-		rv := reflect.ValueOf(v)
-		rv = makeAddressable(rv)
-		d.printValue(w, rv, 0, state)
-		fmt.Fprintln(w)
+func (d *Dumper) writeDump(w io.Writer, vs ...any) {
+	// Values are inspected as they are printed, so output starts before
+	// the whole value has been walked.
+	state, values := d.newInspection(vs)
+	state.stream = true
+	if state.budget == nil {
+		for i := range vs {
+			d.printNode(w, d.inspectAt(vs, values, i, state), 0)
+			fmt.Fprintln(w)
+		}
+		return
+	}
+
+	bw := &budgetWriter{w: w, b: state.budget}
+	for i := range vs {
+		d.printNode(bw, d.inspectAt(vs, values, i, state), 0)
+		fmt.Fprintln(bw)
+	}
+	_ = bw.Flush()
+	if state.budget.exceeded {
+		fmt.Fprintln(w, d.colorize(RoleMuted, state.budget.marker()))
 	}
 }
 
+// typeString returns the cached display name of t.
+func (d *Dumper) typeString(t reflect.Type) string {
+	return d.plan(t).typeString
+}
+
 func (d *Dumper) getTypeString(t reflect.Type) string {
 	switch t.Kind() {
 	case reflect.Map:
@@ -903,200 +995,257 @@ func (d *Dumper) getTypeString(t reflect.Type) string {
 	}
 }
 
+// printValue renders v at the given indent level.
 func (d *Dumper) printValue(w io.Writer, v reflect.Value, indent int, state *dumpState) {
-	if !v.IsValid() {
-		fmt.Fprint(w, d.colorize(colorGray, "<invalid>"))
-		return
-	}
+	d.printNode(w, d.inspectValue(v, indent, state), indent)
+}
 
-	if isNil(v) {
-		typeStr := d.getTypeString(v.Type())
-		fmt.Fprintf(w, d.colorize(colorLime, typeStr)+d.colorize(colorGray, "(nil)"))
+// printNode renders n as text at the given indent level.
+func (d *Dumper) printNode(w io.Writer, n *Node, indent int) {
+	switch {
+	case n.Kind == NodeNil:
+		fmt.Fprint(w, d.colorize(RoleNil, n.Type)+d.colorize(RoleMuted, "(nil)"))
 		return
-	}
-
-	if shouldTruncateAtDepth(v, indent, d.maxDepth) {
-		fmt.Fprint(w, d.colorize(colorGray, "... (max depth)"))
+	case n.Kind == NodeRef:
+		if n.Ref == 0 {
+			// The value was shown without an anchor to refer back to.
+			fmt.Fprint(w, d.colorize(RoleRef, "↩︎")+d.colorize(RoleType, " #"+n.Type))
+			return
+		}
+		fmt.Fprint(w, d.colorize(RoleRef, fmt.Sprintf("↩︎ &%d", n.Ref)))
 		return
-	}
-
-	if s := d.asStringer(v); s != "" {
-		fmt.Fprint(w, s)
+	case n.Redacted:
+		fmt.Fprint(w, d.redactedText(n.Type))
 		return
-	}
-
-	switch v.Kind() {
-	case reflect.Chan:
-		typ := d.colorizer(colorGray, d.getTypeString(v.Type()))
-		fmt.Fprintf(w, "%s(%s)", d.colorize(colorGray, typ), d.colorize(colorCyan, fmt.Sprintf("%#x", v.Pointer())))
+	case n.MaxDepth && n.Kind != NodeChan && n.Kind != NodeFunc:
+		fmt.Fprint(w, d.colorize(RoleMuted, "... (max depth)"))
+		return
+	case n.Kind == NodeInvalid:
+		fmt.Fprint(w, d.colorize(RoleMuted, "<invalid>"))
 		return
 	}
 
-	if v.Kind() == reflect.Ptr && v.CanAddr() {
-		ptr := v.Pointer()
-		if id, ok := state.refs[ptr]; ok {
-			fmt.Fprintf(w, d.colorize(colorRef, "↩︎ &%d"), id)
+	switch n.Kind {
+	case NodeText:
+		// Closure captures have a type but no value.
+		if n.Value == "" && n.Type != "" {
+			fmt.Fprint(w, d.colorize(RoleType, "#"+n.Type))
+			return
+		}
+		fmt.Fprint(w, d.colorize(RoleText, d.sanitize(n.Value)))
+		if n.Type != "" {
+			fmt.Fprint(w, d.colorize(RoleType, " #"+n.Type))
+		}
+		return
+	case NodeError:
+		if len(n.Children) == 0 {
+			fmt.Fprint(w, d.quotedString(n.Value, n.Truncated)+d.colorize(RoleType, " #"+n.Type))
 			return
-		} else {
-			state.refs[ptr] = state.nextRefID
-			state.nextRefID++
 		}
+		d.printBlock(w, n, indent)
+		return
+	case NodeExpanded:
+		d.printBlock(w, n, indent)
+		return
+	case NodeChan:
+		d.printChan(w, n, indent)
+		return
+	}
+
+	if d.printCompact(w, n, indent) {
+		return
 	}
 
-	// We don't need to check any previous checks (validity, channel, nil,
-	// addressable pointer) since they all work directly on the pointer type. We
-	// can simply continue with the reference value from here and add a pointer
-	// prefix to the output.
-	ptrPrefix := ""
-	for v.Kind() == reflect.Ptr {
-		ptrPrefix += "*"
-		v = v.Elem()
+	if n.ID > 0 {
+		fmt.Fprint(w, d.colorize(RoleRef, fmt.Sprintf("&%d", n.ID))+" ")
 	}
 
-	switch v.Kind() {
-	case reflect.Interface:
-		d.printValue(w, v.Elem(), indent, state)
-	case reflect.Struct:
-		t := v.Type()
-		fmt.Fprintf(w, "%s {", d.colorize(colorGray, fmt.Sprintf("#%s%s", ptrPrefix, d.getTypeString(v.Type()))))
+	addrTag := d.addressTag(n)
+	switch n.Kind {
+	case NodeInterface:
+		d.printNode(w, n.Children[0], indent)
+		fmt.Fprint(w, addrTag)
+	case NodeStruct:
+		fmt.Fprintf(w, "%s%s {", d.colorize(RoleType, "#"+n.Type), addrTag)
 		fmt.Fprintln(w)
 
-		for i := 0; i < t.NumField(); i++ {
-			field := t.Field(i)
-			fieldVal := v.Field(i)
-			if !d.shouldIncludeField(field.Name) {
-				continue
+		// Fields still to be inspected are aligned by the labels they may
+		// have.
+		names := n.fieldLabels
+		if names == nil {
+			names = make([]string, len(n.Children))
+			for i, f := range n.Children {
+				names[i] = f.Name
 			}
-
+		}
+		labels := make([]string, len(names))
+		for i, name := range names {
+			labels[i] = d.sanitize(name)
+		}
+		width := d.labelWidth(labels)
+		n.eachChild(func(f *Node) {
 			symbol := "+"
-			if field.PkgPath != "" {
+			if f.Unexported {
 				symbol = "-"
-				fieldVal = forceExported(fieldVal)
-			}
-			indentPrint(w, indent+1, d.colorize(colorYellow, symbol)+field.Name)
-			fmt.Fprint(w, "	=> ")
-			if d.shouldRedactField(field.Name) {
-				fmt.Fprint(w, d.redactedValue(fieldVal))
-			} else {
-				d.printValue(w, fieldVal, indent+1, state)
 			}
+			indentPrint(w, indent+1, d.colorize(RoleVisibility, symbol)+d.colorize(RoleField, d.padLabel(d.sanitize(f.Name), width))+" => ")
+			d.printNode(w, f, indent+1)
+			fmt.Fprintln(w)
+		})
+		if n.Truncated {
+			indentPrint(w, indent+1, d.colorize(RoleMuted, "... (truncated)"))
 			fmt.Fprintln(w)
 		}
 		indentPrint(w, indent, "")
 		fmt.Fprint(w, "}")
-	case reflect.Complex64, reflect.Complex128:
-		fmt.Fprint(w, d.colorize(colorCyan, fmt.Sprintf("%v", v.Complex())))
-	case reflect.UnsafePointer:
-		fmt.Fprint(w, d.colorize(colorGray, fmt.Sprintf("unsafe.Pointer(%#x)", v.Pointer())))
-	case reflect.Map:
-		fmt.Fprintf(w, "%s {", d.colorize(colorGray, fmt.Sprintf("#%s%s", ptrPrefix, d.getTypeString(v.Type()))))
+	case NodeMap:
+		fmt.Fprintf(w, "%s%s {", d.colorize(RoleType, "#"+n.Type), addrTag)
 		fmt.Fprintln(w)
 
-		keys := v.MapKeys()
-		for i, key := range keys {
-			if i >= d.maxItems {
-				indentPrint(w, indent+1, d.colorize(colorGray, "... (truncated)"))
-				break
-			}
-			keyStr := fmt.Sprintf("%v", key.Interface())
-			indentPrint(w, indent+1, fmt.Sprintf(" %s => ", d.colorize(colorMeta, keyStr)))
-			d.printValue(w, v.MapIndex(key), indent+1, state)
+		n.eachChild(func(e *Node) {
+			indentPrint(w, indent+1, fmt.Sprintf(" %s => ", d.colorize(RoleKey, d.sanitize(e.Name))))
+			d.printNode(w, e, indent+1)
 			fmt.Fprintln(w)
+		})
+		if n.Truncated {
+			indentPrint(w, indent+1, d.colorize(RoleMuted, "... (truncated)"))
 		}
 		indentPrint(w, indent, "")
 		fmt.Fprint(w, "}")
-	case reflect.Slice, reflect.Array:
-		// []byte handling
-		if v.Type().Elem().Kind() == reflect.Uint8 {
-			if v.CanConvert(reflect.TypeOf([]byte{})) { // Check if it can be converted to []byte
-				if data, ok := v.Convert(reflect.TypeOf([]byte{})).Interface().([]byte); ok {
-					hexDump := d.formatByteSliceAsHexDump(data, indent+1)
-					fmt.Fprint(w, d.colorize(colorLime, hexDump))
-					break
-				}
-			}
-		}
-
-		// Default rendering for other slices/arrays
-		fmt.Fprintf(w, "%s [", d.colorize(colorGray, fmt.Sprintf("#%s%s", ptrPrefix, d.getTypeString(v.Type()))))
+	case NodeBytes:
+		fmt.Fprint(w, d.formatByteSliceAsHexDump(n.Bytes, indent+1, addrTag))
+	case NodeList:
+		fmt.Fprintf(w, "%s%s [", d.colorize(RoleType, "#"+n.Type), addrTag)
 		fmt.Fprintln(w)
 
-		for i := 0; i < v.Len(); i++ {
-			if i >= d.maxItems {
-				indentPrint(w, indent+1, d.colorize(colorGray, "... (truncated)\n"))
-				break
-			}
-			indentPrint(w, indent+1, fmt.Sprintf("%s => ", d.colorize(colorCyan, fmt.Sprintf("%d", i))))
-			d.printValue(w, v.Index(i), indent+1, state)
+		n.eachChild(func(e *Node) {
+			indentPrint(w, indent+1, fmt.Sprintf("%s => ", d.colorize(RoleIndex, e.Name)))
+			d.printNode(w, e, indent+1)
 			fmt.Fprintln(w)
+		})
+		if n.Truncated {
+			indentPrint(w, indent+1, d.colorize(RoleMuted, "... (truncated)\n"))
 		}
 		indentPrint(w, indent, "")
 		fmt.Fprint(w, "]")
-	case reflect.String:
-		str := escapeControl(v.String())
-		if utf8.RuneCountInString(str) > d.maxStringLen {
-			runes := []rune(str)
-			str = string(runes[:d.maxStringLen]) + "…"
-		}
-		fmt.Fprint(w, d.colorize(colorYellow, `"`)+d.colorize(colorLime, str)+d.colorize(colorYellow, `"`))
-	case reflect.Bool:
-		if v.Bool() {
-			fmt.Fprint(w, d.colorize(colorYellow, "true"))
-		} else {
-			fmt.Fprint(w, d.colorize(colorGray, "false"))
+	case NodeFunc:
+		d.printFunc(w, n, indent)
+	case NodeUnsafePointer:
+		fmt.Fprint(w, d.colorize(RoleNumber, "unsafe.Pointer("+n.Value+")"))
+	default:
+		fmt.Fprint(w, d.scalarString(n))
+		// The message of an error block has no type of its own.
+		if n.Type != "" {
+			fmt.Fprint(w, d.colorize(RoleType, " #"+n.Type)+addrTag)
 		}
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		fmt.Fprint(w, d.colorize(colorCyan, fmt.Sprint(v.Int())))
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		fmt.Fprint(w, d.colorize(colorCyan, fmt.Sprint(v.Uint())))
-	case reflect.Float32, reflect.Float64:
-		fmt.Fprint(w, d.colorize(colorCyan, fmt.Sprintf("%f", v.Float())))
-	case reflect.Func:
-		fmt.Fprint(w, d.colorize(colorGray, v.Type().String()))
-	}
-
-	// These types should not have post types since they have a body and already
-	// had their type written out.
-	if contains([]reflect.Kind{
-		reflect.Struct,
-		reflect.UnsafePointer,
-		reflect.Map,
-		reflect.Slice,
-		reflect.Array,
-		reflect.Ptr,
-		reflect.Interface,
-	}, v.Kind()) {
-		return
 	}
+}
+
+// printBlock renders the labeled children of n, such as expander output or
+// the parts of an error, as a block.
+func (d *Dumper) printBlock(w io.Writer, n *Node, indent int) {
+	fmt.Fprintf(w, "%s {", d.colorize(RoleType, "#"+n.Type))
+	fmt.Fprintln(w)
+
+	labels := make([]string, len(n.Children))
+	for i, child := range n.Children {
+		labels[i] = d.sanitize(child.Name)
+	}
+	width := d.labelWidth(labels)
+	for i, child := range n.Children {
+		indentPrint(w, indent+1, d.colorize(RoleKey, d.padLabel(labels[i], width))+" => ")
+		d.printNode(w, child, indent+1)
+		fmt.Fprintln(w)
+	}
+	// An error's Truncated flag refers to its message, which the
+	// "Error" child already shows cut.
+	if n.Truncated && n.Kind != NodeError {
+		indentPrint(w, indent+1, d.colorize(RoleMuted, "... (truncated)"))
+		fmt.Fprintln(w)
+	}
+	indentPrint(w, indent, "")
+	fmt.Fprint(w, "}")
+}
+
+// scalarString renders a string, bool or number node without its type.
+func (d *Dumper) scalarString(n *Node) string {
+	if n.Kind == NodeString {
+		return d.quotedString(n.Value, n.Truncated)
+	}
+	if _, ok := n.Raw.(bool); ok {
+		return d.colorize(RoleBool, n.Value)
+	}
+	return d.colorize(RoleNumber, n.Value)
+}
 
-	fmt.Fprint(w, d.colorizer(colorGray, fmt.Sprintf(" #%s%s", ptrPrefix, d.getTypeString(v.Type()))))
+// quotedString escapes and quotes a string for display, marking text cut by
+// WithMaxStringLen with an ellipsis.
+func (d *Dumper) quotedString(s string, truncated bool) string {
+	str := d.sanitize(s)
+	if truncated {
+		str += "…"
+	}
+	return d.colorize(RoleQuote, `"`) + d.colorize(RoleString, str) + d.colorize(RoleQuote, `"`)
 }
 
-// asStringer checks if the value implements fmt.Stringer and returns its string representation.
-func (d *Dumper) asStringer(v reflect.Value) string {
-	if d.disableStringer {
-		return ""
+// stringerText returns the String result for a value printed through
+// fmt.Stringer. A nil pointer receiver is reported with nilPtr instead of
+// being called.
+func (d *Dumper) stringerText(v reflect.Value) (s string, nilPtr, ok bool) {
+	if d.disableStringer || !d.plan(v.Type()).stringer {
+		return "", false, false
 	}
 
 	val := v
 	if !val.CanInterface() {
 		val = forceExported(val)
 	}
-	if val.CanInterface() {
-		if s, ok := val.Interface().(fmt.Stringer); ok {
-			rv := reflect.ValueOf(s)
-			if rv.Kind() == reflect.Ptr && rv.IsNil() {
-				return d.colorize(colorGray, val.Type().String()+"(nil)")
-			}
-			return d.colorize(colorLime, s.String()) + d.colorize(colorGray, " #"+d.getTypeString(val.Type()))
+	if !val.CanInterface() {
+		return "", false, false
+	}
+	stringer, ok := val.Interface().(fmt.Stringer)
+	if !ok {
+		return "", false, false
+	}
+	if rv := reflect.ValueOf(stringer); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return "", true, true
+	}
+	return stringer.String(), false, true
+}
+
+// labelWidth returns the visible width of the widest label in a block.
+func (d *Dumper) labelWidth(labels []string) int {
+	width := 0
+	for _, label := range labels {
+		if n := d.visibleWidth(label); n > width {
+			width = n
 		}
 	}
-	return ""
+	return width
+}
+
+// padLabel pads label with spaces to width visible columns, so the "=>"
+// separators of a block line up.
+func (d *Dumper) padLabel(label string, width int) string {
+	if n := d.visibleWidth(label); n < width {
+		return label + strings.Repeat(" ", width-n)
+	}
+	return label
+}
+
+// visibleWidth counts the runes of s that are displayed, ignoring ANSI escape
+// sequences and, in HTML output, color spans and entity escapes.
+func (d *Dumper) visibleWidth(s string) int {
+	s = stripANSI(s)
+	if d.htmlOutput {
+		s = html.UnescapeString(stripHTMLSpans(s))
+	}
+	return utf8.RuneCountInString(s)
 }
 
 // indentPrint prints indented text to the writer.
 func indentPrint(w io.Writer, indent int, text string) {
-	fmt.Fprint(w, strings.Repeat(" ", indent*indentWidth)+text)
+	io.WriteString(w, strings.Repeat(" ", indent*indentWidth)+text)
 }
 
 // forceExported returns a value that is guaranteed to be exported, even if it is unexported.
@@ -1138,51 +1287,6 @@ func isNil(v reflect.Value) bool {
 	}
 }
 
-// replacer is used to escape control characters in strings.
-var replacer = strings.NewReplacer(
-	"\n", `\n`,
-	"\t", `\t`,
-	"\r", `\r`,
-	"\v", `\v`,
-	"\f", `\f`,
-	"\x1b", `\x1b`,
-)
-
-// escapeControl escapes control characters in a string for safe display.
-func escapeControl(s string) string {
-	return replacer.Replace(s)
-}
-
-// detectColor checks environment variables to determine if color output should be enabled.
-func detectColor() bool {
-	if os.Getenv("NO_COLOR") != "" {
-		return false
-	}
-	if os.Getenv("FORCE_COLOR") != "" {
-		return true
-	}
-	return true
-}
-
-// newColorizer picks the appropriate colorizer based on environment overrides.
-func newColorizer() Colorizer {
-	if detectColor() {
-		return colorizeANSI
-	}
-	return colorizeUnstyled
-}
-
-// contains reports whether target exists in the candidates slice.
-func contains(candidates []reflect.Kind, target reflect.Kind) bool {
-	for _, candidate := range candidates {
-		if candidate == target {
-			return true
-		}
-	}
-
-	return false
-}
-
 // shouldIncludeField returns true when the field survives include/exclude filtering (include takes precedence).
 func (d *Dumper) shouldIncludeField(name string) bool {
 	if len(d.includeFields) > 0 && !d.matchesAny(name, d.includeFields, FieldMatchExact) {
@@ -1229,12 +1333,12 @@ func (d *Dumper) matchesAny(name string, candidates []string, mode FieldMatchMod
 	return false
 }
 
-func (d *Dumper) redactedValue(v reflect.Value) string {
-	if !v.IsValid() {
-		return d.colorize(colorRed, "<redacted>")
+// redactedText renders the placeholder of a redacted value of type typeStr.
+func (d *Dumper) redactedText(typeStr string) string {
+	if typeStr == "" {
+		return d.colorize(RoleRedacted, "<redacted>")
 	}
-	typeStr := d.getTypeString(v.Type())
-	return d.colorize(colorRed, "<redacted>") + d.colorize(colorGray, " #"+typeStr)
+	return d.colorize(RoleRedacted, "<redacted>") + d.colorize(RoleType, " #"+typeStr)
 }
 
 // isComplexValue reports whether v unwraps to a struct/map/slice/array.
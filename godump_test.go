@@ -371,8 +371,9 @@ func TestStringerNilPointer(t *testing.T) {
 	assert.NotContains(t, out, "... (max depth)")
 
 	tptr = nil
-	out = d.asStringer(reflect.ValueOf(tptr))
-	assert.Contains(t, out, "time.Time(nil)")
+	_, nilPtr, ok := d.stringerText(reflect.ValueOf(tptr))
+	assert.True(t, ok)
+	assert.True(t, nilPtr)
 }
 
 func TestMapOutput(t *testing.T) {
@@ -509,23 +510,28 @@ func TestForceExported(t *testing.T) {
 
 func TestDetectColorVariants(t *testing.T) {
 	t.Run("no environment variables", func(t *testing.T) {
-		assert.True(t, detectColor())
+		unsetColorEnv(t)
+		var buf bytes.Buffer
+		assert.Equal(t, colorDepthNone, detectColorDepth(&buf))
 
-		out := NewDumper().colorize(colorYellow, "test")
-		assert.Equal(t, string(ansiEscape)+"[33mtest"+string(ansiEscape)+"[0m", out)
+		out := NewDumper(WithWriter(&buf)).colorize(colorYellow, "test")
+		assert.Equal(t, "test", out)
 	})
 
 	t.Run("forcing no color", func(t *testing.T) {
+		unsetColorEnv(t)
 		t.Setenv("NO_COLOR", "1")
-		assert.False(t, detectColor())
+		t.Setenv("FORCE_COLOR", "1")
+		assert.Equal(t, colorDepthNone, detectColorDepth(os.Stdout))
 
 		out := NewDumper().colorize(colorYellow, "test")
 		assert.Equal(t, "test", out)
 	})
 
 	t.Run("forcing color", func(t *testing.T) {
+		unsetColorEnv(t)
 		t.Setenv("FORCE_COLOR", "1")
-		assert.True(t, detectColor())
+		assert.Equal(t, colorDepth16, detectColorDepth(&bytes.Buffer{}))
 
 		out := NewDumper().colorize(colorYellow, "test")
 		assert.Equal(t, string(ansiEscape)+"[33mtest"+string(ansiEscape)+"[0m", out)
@@ -566,6 +572,8 @@ func TestEnsureColorizer(t *testing.T) {
 	})
 
 	t.Run("detect color", func(t *testing.T) {
+		unsetColorEnv(t)
+		t.Setenv("FORCE_COLOR", "1")
 		d := NewDumper()
 
 		d.ensureColorizer()
@@ -584,10 +592,9 @@ func TestEnsureColorizer(t *testing.T) {
 }
 
 func TestHtmlColorizeUnknown(t *testing.T) {
-	// Color not in htmlColorMap
-	out := colorizeHTML(string(ansiEscape)+"[999m", "test")
-	assert.Contains(t, out, `<span style="color:`)
-	assert.Contains(t, out, "test")
+	// Codes other than roles have no HTML color, so no span is emitted.
+	out := colorizeHTML(string(ansiEscape)+"[999m", "<test>")
+	assert.Equal(t, "&lt;test&gt;", out)
 }
 
 func TestUnreadableFallback(t *testing.T) {
@@ -647,7 +654,7 @@ func TestPrimitiveTypes(t *testing.T) {
 	assert.Contains(t, out, "3 #uint8")
 	assert.Contains(t, out, "4 #uint16")
 	assert.Contains(t, out, "5 #uintptr")
-	assert.Contains(t, out, "1.500000 #float32")
+	assert.Contains(t, out, "1.5 #float32")
 	assert.Contains(t, out, "0 => 6 #int") // array
 	assert.Contains(t, out, "42 #int")     // interface{}
 }
@@ -1032,7 +1039,7 @@ func TestTheKitchenSink(t *testing.T) {
 	assert.Contains(t, out, "+Int")
 	assert.Contains(t, out, "42 #int")
 	assert.Contains(t, out, "+Float")
-	assert.Contains(t, out, "3.141500 #float64")
+	assert.Contains(t, out, "3.1415 #float64")
 	assert.Contains(t, out, "+PtrString")
 	assert.Contains(t, out, `"Hello" #*string`)
 	assert.Contains(t, out, "+PtrDuration")
@@ -1131,8 +1138,9 @@ func TestAsStringer_ForceExported(t *testing.T) {
 
 	assert.False(t, v.CanInterface(), "field must not be interfaceable")
 
-	str := newDumperT(t).asStringer(v)
+	str, _, ok := newDumperT(t).stringerText(v)
 
+	assert.True(t, ok)
 	assert.Contains(t, str, "👻 hidden stringer")
 }
 
@@ -1224,6 +1232,71 @@ func TestDumpWithCustomWriter(t *testing.T) {
 	}
 }
 
+// recordingWriter records the size of every write it receives.
+type recordingWriter struct {
+	writes []int
+	total  int
+}
+
+func (r *recordingWriter) Write(p []byte) (int, error) {
+	r.writes = append(r.writes, len(p))
+	r.total += len(p)
+	return len(p), nil
+}
+
+// writeProbe records how much had been written to w when it was printed.
+type writeProbe struct {
+	w       *recordingWriter
+	written *int
+}
+
+func (p writeProbe) String() string {
+	*p.written = p.w.total
+	return "probe"
+}
+
+func TestDumpStreamsToWriter(t *testing.T) {
+	var w recordingWriter
+	written := -1
+	items := make([]any, 20001)
+	for i := range items {
+		items[i] = i
+	}
+	items[len(items)-1] = writeProbe{w: &w, written: &written}
+
+	NewDumper(WithWriter(&w), WithoutColor(), WithMaxItems(len(items))).Dump(items)
+
+	// The last element is only inspected once the ones before it have been
+	// written out.
+	assert.True(t, written > 20000*10)
+	assert.True(t, len(w.writes) > 1)
+	for _, n := range w.writes {
+		assert.True(t, n <= 4096)
+	}
+	assert.True(t, w.total-written < 4096+100)
+}
+
+func TestAlignmentIsPerBlock(t *testing.T) {
+	type Inner struct {
+		A     int
+		Bravo int
+	}
+	type Outer struct {
+		ID                int
+		ExtremelyLongName Inner
+	}
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(Outer{ID: 1, ExtremelyLongName: Inner{A: 2, Bravo: 3}})
+	assert.Equal(t, `#godump.Outer {
+  +ID                => 1 #int
+  +ExtremelyLongName => #godump.Inner {
+    +A     => 2 #int
+    +Bravo => 3 #int
+  }
+}
+`, out)
+}
+
 func wrappedDumpStr(skip int, v any) string {
 	return NewDumper(WithSkipStackFrames(skip)).DumpStr(v)
 }
@@ -1353,10 +1426,10 @@ func TestDumpJSON(t *testing.T) {
 		assert.JSONEq(t, expected, jsonStr)
 	})
 
-	t.Run("unmarshallable type", func(t *testing.T) {
+	t.Run("channel", func(t *testing.T) {
 		ch := make(chan int)
 		jsonStr := DumpJSONStr(ch)
-		expected := `{"error": "json: unsupported type: chan int"}`
+		expected := `"chan int (len=0 cap=0)"`
 		assert.JSONEq(t, expected, jsonStr)
 	})
 
@@ -1555,7 +1628,7 @@ func TestRedactMatchSuffixAndEmptyCandidate(t *testing.T) {
 	assert.Contains(t, out, "+Email")
 	assert.NotContains(t, out, "abc")
 
-	placeholder := d.redactedValue(reflect.Value{})
+	placeholder := d.redactedText("")
 	assert.Contains(t, placeholder, "<redacted>")
 }
 
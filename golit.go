@@ -0,0 +1,510 @@
+package godump
+
+import (
+	"fmt"
+	"go/format"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// goLitContext describes where a literal is being emitted, which decides
+// whether its type must be spelled out.
+type goLitContext int
+
+const (
+	// goCtxTyped is a position with a known static type, such as a struct field.
+	goCtxTyped goLitContext = iota
+	// goCtxElided is a slice, array or map element whose composite type may be omitted.
+	goCtxElided
+	// goCtxInterface is a position whose static type is an interface, so scalars need conversions.
+	goCtxInterface
+)
+
+// goRefKey identifies a pointer, map or slice by address and type.
+type goRefKey struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+// goLitState tracks shared pointers and hoisted declarations for a single literal.
+type goLitState struct {
+	counts  map[goRefKey]int
+	names   map[goRefKey]string
+	active  map[goRefKey]bool
+	decls   []string
+	nextVar int
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// goLitPrefix wraps an expression so go/format accepts it as a declaration.
+const goLitPrefix = "package p\n\nvar _ = "
+
+// DumpGo prints the values as gofmt-formatted Go literals.
+// @group Go
+//
+// Example: print a Go literal
+//
+//	type User struct {
+//		Name string
+//		Tags []string
+//	}
+//	godump.DumpGo(&User{Name: "Alice", Tags: []string{"admin"}})
+//	// &main.User{
+//	// 	Name: "Alice",
+//	// 	Tags: []string{
+//	// 		"admin",
+//	// 	},
+//	// }
+func DumpGo(vs ...any) {
+	defaultDumper.DumpGo(vs...)
+}
+
+// DumpGo prints the values as gofmt-formatted Go literals to the configured writer.
+// @group Go
+//
+// Example: print a Go literal with a custom dumper
+//
+//	d := godump.NewDumper()
+//	d.DumpGo(map[string]int{"a": 1})
+//	// map[string]int{
+//	// 	"a": 1,
+//	// }
+func (d *Dumper) DumpGo(vs ...any) {
+	writeLocked(d.writer, d.DumpGoStr(vs...)+"\n")
+}
+
+// DumpGoStr returns the values as gofmt-formatted Go literals.
+// @group Go
+//
+// Example: get a Go literal
+//
+//	out := godump.DumpGoStr([]int{1, 2})
+//	_ = out
+//	// []int{
+//	// 	1,
+//	// 	2,
+//	// }
+func DumpGoStr(vs ...any) string {
+	return defaultDumper.DumpGoStr(vs...)
+}
+
+// DumpGoStr returns the values as gofmt-formatted Go literals.
+// Values reached through more than one pointer are hoisted into variables of
+// an immediately invoked function, unexported and redacted fields become
+// comments, and cycles that cannot be expressed are emitted as annotated nils.
+// @group Go
+//
+// Example: get a Go literal with a custom dumper
+//
+//	d := godump.NewDumper()
+//	out := d.DumpGoStr(int8(3))
+//	_ = out
+//	// int8(3)
+func (d *Dumper) DumpGoStr(vs ...any) string {
+	parts := make([]string, 0, len(vs))
+	for _, v := range vs {
+		parts = append(parts, d.goLiteralStr(makeAddressable(reflect.ValueOf(v))))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// goLiteralStr renders a single top-level value as a formatted Go expression.
+func (d *Dumper) goLiteralStr(v reflect.Value) string {
+	// Integers keep the configured base, but only "_" is a valid Go digit separator.
+	if sep := d.numberFormat.Separator; sep != "" && sep != "_" {
+		d = d.clone()
+		d.numberFormat.Separator = ""
+	}
+
+	st := &goLitState{
+		counts:  map[goRefKey]int{},
+		names:   map[goRefKey]string{},
+		active:  map[goRefKey]bool{},
+		nextVar: 1,
+	}
+	countGoRefs(v, st.counts)
+
+	expr := d.goLiteral(v, goCtxInterface, 0, st, false)
+	if len(st.decls) > 0 {
+		expr = fmt.Sprintf("func() %s {\n%s\nreturn %s\n}()", d.typeString(v.Type()), strings.Join(st.decls, "\n"), expr)
+	}
+	return formatGoExpr(expr)
+}
+
+// formatGoExpr gofmt-formats an expression, returning it unchanged if it does not parse.
+func formatGoExpr(expr string) string {
+	src, err := format.Source([]byte(goLitPrefix + expr))
+	if err != nil {
+		return expr
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(string(src), goLitPrefix), "\n")
+}
+
+// countGoRefs counts how many times each pointer is reached so shared values can be hoisted.
+func countGoRefs(v reflect.Value, counts map[goRefKey]int) {
+	if !v.IsValid() || isNil(v) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		key := goRefKey{ptr: v.Pointer(), typ: v.Type()}
+		counts[key]++
+		if counts[key] == 1 {
+			countGoRefs(v.Elem(), counts)
+		}
+	case reflect.Interface:
+		countGoRefs(v.Elem(), counts)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			countGoRefs(v.Field(i), counts)
+		}
+	case reflect.Map:
+		key := goRefKey{ptr: v.Pointer(), typ: v.Type()}
+		counts[key]++
+		if counts[key] > 1 {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			countGoRefs(iter.Key(), counts)
+			countGoRefs(iter.Value(), counts)
+		}
+	case reflect.Slice:
+		key := goRefKey{ptr: v.Pointer(), len: v.Len(), typ: v.Type()}
+		counts[key]++
+		if counts[key] > 1 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			countGoRefs(v.Index(i), counts)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			countGoRefs(v.Index(i), counts)
+		}
+	}
+}
+
+// goLiteral renders v as a Go expression. When comment is set the result is
+// kept on one line and nothing is hoisted, so it can be embedded in a comment.
+func (d *Dumper) goLiteral(v reflect.Value, ctx goLitContext, depth int, st *goLitState, comment bool) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+
+	typeStr := d.typeString(v.Type())
+	if isNil(v) {
+		if ctx == goCtxInterface && v.Kind() != reflect.Interface {
+			return "(" + typeStr + ")(nil)"
+		}
+		return "nil"
+	}
+
+	if shouldTruncateAtDepth(v, depth, d.maxDepth) {
+		return d.goZero(v.Type(), ctx) + " /* ... (max depth) */"
+	}
+
+	if v.Type() == timeType {
+		return goTimeLiteral(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		return d.goLiteral(v.Elem(), goCtxInterface, depth, st, comment)
+	case reflect.Ptr:
+		return d.goPointer(v, ctx, depth, st, comment)
+	case reflect.Struct:
+		return d.goStruct(v, ctx, depth, st, comment)
+	case reflect.Map:
+		return d.goMap(v, ctx, depth, st, comment)
+	case reflect.Slice, reflect.Array:
+		return d.goList(v, ctx, depth, st, comment)
+	case reflect.String:
+		return goConvert(v.Type(), ctx, d.goString(v.String()))
+	case reflect.Bool:
+		return goConvert(v.Type(), ctx, strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return goConvert(v.Type(), ctx, d.formatInt(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return goConvert(v.Type(), ctx, d.formatUint(v.Uint()))
+	case reflect.Float32, reflect.Float64:
+		return goFloat(v.Type(), ctx, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		bits := floatBits(v.Kind() == reflect.Complex64)
+		lit := fmt.Sprintf("complex(%s, %s)", goFloatLit(real(c), bits), goFloatLit(imag(c), bits))
+		return goConvert(v.Type(), ctx, lit)
+	case reflect.Chan:
+		return fmt.Sprintf("make(%s, %d)", typeStr, v.Cap())
+	default:
+		// Funcs and unsafe pointers have no literal form.
+		return "nil /* " + typeStr + " */"
+	}
+}
+
+// goPointer renders a pointer inline, as a reference to a hoisted variable, or as a flagged cycle.
+func (d *Dumper) goPointer(v reflect.Value, ctx goLitContext, depth int, st *goLitState, comment bool) string {
+	key := goRefKey{ptr: v.Pointer(), typ: v.Type()}
+	if st.active[key] {
+		return "nil /* cycle: ↩︎ " + d.typeString(v.Type()) + " */"
+	}
+	if name, ok := st.names[key]; ok && !comment {
+		return "&" + name
+	}
+
+	st.active[key] = true
+	defer delete(st.active, key)
+
+	elem := v.Elem()
+	if comment || (st.counts[key] <= 1 && isGoComposite(elem)) {
+		if ctx == goCtxElided && isGoComposite(elem) {
+			return d.goLiteral(elem, goCtxElided, depth, st, comment)
+		}
+		return "&" + d.goLiteral(elem, goCtxTyped, depth, st, comment)
+	}
+
+	name := fmt.Sprintf("v%d", st.nextVar)
+	st.nextVar++
+	lit := d.goLiteral(elem, goCtxInterface, depth, st, comment)
+	if elem.Kind() == reflect.Interface || isNil(elem) {
+		st.decls = append(st.decls, fmt.Sprintf("var %s %s = %s", name, d.typeString(elem.Type()), lit))
+	} else {
+		st.decls = append(st.decls, name+" := "+lit)
+	}
+	st.names[key] = name
+	return "&" + name
+}
+
+// goStruct renders a struct literal, turning unexported and redacted fields into comments.
+func (d *Dumper) goStruct(v reflect.Value, ctx goLitContext, depth int, st *goLitState, comment bool) string {
+	t := v.Type()
+	var entries []string
+	for _, f := range d.plan(t).fields {
+		fd := d.fieldDumper(f.tag, depth+1)
+		switch {
+		case f.redact:
+			entries = append(entries, goComment(f.name+": <redacted>", comment))
+		case !f.exported:
+			val := fd.goLiteral(forceExported(v.Field(f.index)), goCtxTyped, depth+1, st, true)
+			entries = append(entries, goComment(f.name+": "+val+" (unexported)", comment))
+		default:
+			entries = append(entries, f.name+": "+fd.goLiteral(v.Field(f.index), goCtxTyped, depth+1, st, comment)+",")
+		}
+	}
+	return d.goCompositeType(t, ctx) + goBody(entries, comment)
+}
+
+// goMap renders a map literal with keys in the configured order.
+func (d *Dumper) goMap(v reflect.Value, ctx goLitContext, depth int, st *goLitState, comment bool) string {
+	key := goRefKey{ptr: v.Pointer(), typ: v.Type()}
+	if st.active[key] {
+		return "nil /* cycle: ↩︎ " + d.typeString(v.Type()) + " */"
+	}
+	st.active[key] = true
+	defer delete(st.active, key)
+
+	keyCtx := goElemContext(v.Type().Key())
+	valCtx := goElemContext(v.Type().Elem())
+
+	var entries []string
+	for i, k := range d.sortedMapKeys(v) {
+		if i >= d.maxItems {
+			entries = append(entries, goComment("... (truncated)", comment))
+			break
+		}
+		keyLit := d.goLiteral(k, keyCtx, depth+1, st, comment)
+		valLit := d.goLiteral(v.MapIndex(k), valCtx, depth+1, st, comment)
+		entries = append(entries, keyLit+": "+valLit+",")
+	}
+	return d.goCompositeType(v.Type(), ctx) + goBody(entries, comment)
+}
+
+// goList renders a slice or array literal; byte slices become string conversions.
+func (d *Dumper) goList(v reflect.Value, ctx goLitContext, depth int, st *goLitState, comment bool) string {
+	t := v.Type()
+	if v.Kind() == reflect.Slice {
+		if t.Elem().Kind() == reflect.Uint8 {
+			return d.getTypeString(t) + "(" + strconv.Quote(string(v.Bytes())) + ")"
+		}
+
+		key := goRefKey{ptr: v.Pointer(), len: v.Len(), typ: t}
+		if st.active[key] {
+			return "nil /* cycle: ↩︎ " + d.getTypeString(t) + " */"
+		}
+		st.active[key] = true
+		defer delete(st.active, key)
+	}
+
+	elemCtx := goElemContext(t.Elem())
+	var entries []string
+	for i := 0; i < v.Len(); i++ {
+		if i >= d.maxItems {
+			entries = append(entries, goComment("... (truncated)", comment))
+			break
+		}
+		entries = append(entries, d.goLiteral(v.Index(i), elemCtx, depth+1, st, comment)+",")
+	}
+	return d.goCompositeType(t, ctx) + goBody(entries, comment)
+}
+
+// goCompositeType returns the type prefix of a composite literal, or nothing when it can be elided.
+func (d *Dumper) goCompositeType(t reflect.Type, ctx goLitContext) string {
+	if ctx == goCtxElided {
+		return ""
+	}
+	return d.getTypeString(t)
+}
+
+// goZero returns a zero value expression for t, used where traversal stops early.
+func (d *Dumper) goZero(t reflect.Type, ctx goLitContext) string {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Array:
+		return d.goCompositeType(t, ctx) + "{}"
+	default:
+		return "nil"
+	}
+}
+
+// goString quotes a string, honoring the configured maximum length.
+func (d *Dumper) goString(s string) string {
+	if utf8.RuneCountInString(s) > d.maxStringLen {
+		runes := []rune(s)
+		return strconv.Quote(string(runes[:d.maxStringLen])) + " /* ... (truncated) */"
+	}
+	return strconv.Quote(s)
+}
+
+// goElemContext returns the context for elements of the given static type.
+func goElemContext(t reflect.Type) goLitContext {
+	if t.Kind() == reflect.Interface {
+		return goCtxInterface
+	}
+	return goCtxElided
+}
+
+// isGoComposite reports whether v renders as a composite literal that can take an & prefix.
+func isGoComposite(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Struct:
+		return v.Type() != timeType
+	case reflect.Array:
+		return true
+	case reflect.Map:
+		return !v.IsNil()
+	case reflect.Slice:
+		return !v.IsNil() && v.Type().Elem().Kind() != reflect.Uint8
+	default:
+		return false
+	}
+}
+
+// goBody joins composite literal entries, one per line unless rendering a comment.
+func goBody(entries []string, comment bool) string {
+	if len(entries) == 0 {
+		return "{}"
+	}
+	if comment {
+		return "{" + strings.TrimSuffix(strings.Join(entries, " "), ",") + "}"
+	}
+	return "{\n" + strings.Join(entries, "\n") + "\n}"
+}
+
+// goComment renders a note as a line comment, or as a block comment when already inside one.
+func goComment(text string, comment bool) string {
+	if comment {
+		return "/* " + text + " */"
+	}
+	return "// " + text
+}
+
+// goConvert wraps a scalar literal in a conversion when the position needs an explicit type.
+func goConvert(t reflect.Type, ctx goLitContext, lit string) string {
+	if ctx != goCtxInterface || t == goDefaultType(t.Kind()) {
+		return lit
+	}
+	return t.String() + "(" + lit + ")"
+}
+
+// goDefaultType returns the type an untyped constant of this kind defaults to.
+func goDefaultType(k reflect.Kind) reflect.Type {
+	switch k {
+	case reflect.String:
+		return reflect.TypeOf("")
+	case reflect.Bool:
+		return reflect.TypeOf(false)
+	case reflect.Int:
+		return reflect.TypeOf(0)
+	case reflect.Float64:
+		return reflect.TypeOf(0.0)
+	case reflect.Complex128:
+		return reflect.TypeOf(complex128(0))
+	default:
+		return nil
+	}
+}
+
+// goFloat renders a float, spelling out NaN, infinities and negative zero via
+// the math package.
+func goFloat(t reflect.Type, ctx goLitContext, f float64) string {
+	lit := goFloatLit(f, floatBits(t.Kind() == reflect.Float32))
+	if strings.HasPrefix(lit, "math.") && t != goDefaultType(reflect.Float64) {
+		return t.String() + "(" + lit + ")"
+	}
+	return goConvert(t, ctx, lit)
+}
+
+// goFloatLit formats a float as the shortest literal that is still a floating-point constant.
+func goFloatLit(f float64, bits int) string {
+	switch {
+	case math.IsNaN(f):
+		return "math.NaN()"
+	case math.IsInf(f, 1):
+		return "math.Inf(1)"
+	case math.IsInf(f, -1):
+		return "math.Inf(-1)"
+	case f == 0 && math.Signbit(f):
+		// The constant -0.0 is positive zero in Go source.
+		return "math.Copysign(0, -1)"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, bits)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// goTimeLiteral renders a time.Time as a time.Date call.
+func goTimeLiteral(v reflect.Value) string {
+	v = forceExported(v)
+	if !v.CanInterface() {
+		return "time.Time{} /* unreadable */"
+	}
+	t, ok := v.Interface().(time.Time)
+	if !ok {
+		return "time.Time{}"
+	}
+	if t.IsZero() {
+		return "time.Time{}"
+	}
+
+	var loc string
+	switch t.Location() {
+	case time.UTC:
+		loc = "time.UTC"
+	case time.Local:
+		loc = "time.Local"
+	default:
+		name, offset := t.Zone()
+		loc = fmt.Sprintf("time.FixedZone(%q, %d)", name, offset)
+	}
+
+	return fmt.Sprintf("time.Date(%d, time.%s, %d, %d, %d, %d, %d, %s)",
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
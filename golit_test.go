@@ -0,0 +1,192 @@
+package godump
+
+import (
+	"bytes"
+	"go/parser"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/goforj/godump/internal/testassert"
+	require "github.com/goforj/godump/internal/testrequire"
+)
+
+type goLitNode struct {
+	Val  int
+	Next *goLitNode
+}
+
+type goLitUser struct {
+	Name    string
+	Tags    []string
+	Created time.Time
+	secret  string
+}
+
+func assertParsesAsGo(t *testing.T, src string) {
+	t.Helper()
+
+	_, err := parser.ParseExpr(src)
+	require.NoError(t, err, src)
+}
+
+func TestDumpGoStrStruct(t *testing.T) {
+	u := &goLitUser{
+		Name:    "Alice",
+		Tags:    []string{"admin", "ops"},
+		Created: time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC),
+		secret:  "shh",
+	}
+
+	out := newDumperT(t).DumpGoStr(u)
+	assertParsesAsGo(t, out)
+	assert.Contains(t, out, "&godump.goLitUser{")
+	assert.Contains(t, out, `Name: "Alice",`)
+	assert.Contains(t, out, "Tags: []string{\n\t\t\"admin\",\n\t\t\"ops\",\n\t},")
+	assert.Contains(t, out, "time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)")
+	assert.Contains(t, out, `// secret: "shh" (unexported)`)
+}
+
+func TestDumpGoStrScalars(t *testing.T) {
+	d := newDumperT(t)
+
+	assert.Equal(t, "42", d.DumpGoStr(42))
+	assert.Equal(t, "int8(3)", d.DumpGoStr(int8(3)))
+	assert.Equal(t, "uint(7)", d.DumpGoStr(uint(7)))
+	assert.Equal(t, "1.0", d.DumpGoStr(1.0))
+	assert.Equal(t, "float32(0.1)", d.DumpGoStr(float32(0.1)))
+	assert.Equal(t, "math.NaN()", d.DumpGoStr(math.NaN()))
+	assert.Equal(t, "float32(math.Inf(-1))", d.DumpGoStr(float32(math.Inf(-1))))
+	assert.Equal(t, "complex(1.0, 2.5)", d.DumpGoStr(complex(1, 2.5)))
+	negZero := math.Copysign(0, -1)
+	assert.Equal(t, "math.Copysign(0, -1)", d.DumpGoStr(negZero))
+	assert.Equal(t, "float32(math.Copysign(0, -1))", d.DumpGoStr(float32(negZero)))
+	assert.Equal(t, "complex(1.0, math.Copysign(0, -1))", d.DumpGoStr(complex(1, negZero)))
+	type celsius float64
+	assert.Equal(t, "godump.celsius(math.Copysign(0, -1))", d.DumpGoStr(celsius(negZero)))
+	assert.Equal(t, `"a\nb"`, d.DumpGoStr("a\nb"))
+	assert.Equal(t, "true", d.DumpGoStr(true))
+	assert.Equal(t, "nil", d.DumpGoStr(nil))
+	assert.Equal(t, "(*int)(nil)", d.DumpGoStr((*int)(nil)))
+	assert.Equal(t, `[]uint8("hi\x00")`, d.DumpGoStr([]byte("hi\x00")))
+	assert.Equal(t, "time.Time{}", d.DumpGoStr(time.Time{}))
+	assert.Equal(t, "make(chan int, 2)", d.DumpGoStr(make(chan int, 2)))
+	assert.Equal(t, "nil /* func() */", d.DumpGoStr(func() {}))
+}
+
+func TestDumpGoStrInterfaceElements(t *testing.T) {
+	out := newDumperT(t).DumpGoStr(map[string]any{"a": int16(1), "b": "x", "c": nil})
+	assertParsesAsGo(t, out)
+	assert.Contains(t, out, `"a": int16(1),`)
+	assert.Contains(t, out, `"b": "x",`)
+	assert.Contains(t, out, `"c": nil,`)
+}
+
+func TestDumpGoStrElidesElementTypes(t *testing.T) {
+	out := newDumperT(t).DumpGoStr([]*goLitNode{{Val: 1}, {Val: 2}})
+	assertParsesAsGo(t, out)
+	assert.Equal(t, 1, strings.Count(out, "goLitNode"))
+	assert.Contains(t, out, "Val:  2,")
+}
+
+func TestDumpGoStrSharedPointers(t *testing.T) {
+	shared := &goLitNode{Val: 7}
+	out := newDumperT(t).DumpGoStr([]*goLitNode{shared, shared})
+	assertParsesAsGo(t, out)
+	assert.Contains(t, out, "func() []*godump.goLitNode {")
+	assert.Contains(t, out, "v1 := godump.goLitNode{")
+	assert.Equal(t, 2, strings.Count(out, "&v1"))
+}
+
+func TestDumpGoStrPointerToScalar(t *testing.T) {
+	n := int32(5)
+	out := newDumperT(t).DumpGoStr(&n)
+	assertParsesAsGo(t, out)
+	assert.Contains(t, out, "v1 := int32(5)")
+	assert.Contains(t, out, "return &v1")
+}
+
+func TestDumpGoStrCycles(t *testing.T) {
+	n := &goLitNode{Val: 1}
+	n.Next = n
+	out := newDumperT(t).DumpGoStr(n)
+	assertParsesAsGo(t, out)
+	assert.Contains(t, out, "/* cycle: ↩︎ *godump.goLitNode */")
+
+	m := map[string]any{}
+	m["self"] = m
+	out = newDumperT(t).DumpGoStr(m)
+	assertParsesAsGo(t, out)
+	assert.Contains(t, out, "/* cycle: ↩︎ map[string]interface {} */")
+}
+
+func TestDumpGoStrAnonymousStructs(t *testing.T) {
+	v := struct {
+		Outer struct {
+			Inner int
+		}
+	}{}
+	v.Outer.Inner = 3
+
+	out := newDumperT(t).DumpGoStr(v)
+	assertParsesAsGo(t, out)
+	assert.Contains(t, out, "Outer: struct{ Inner int }{")
+	assert.Contains(t, out, "Inner: 3,")
+}
+
+func TestDumpGoStrHonorsOptions(t *testing.T) {
+	type Account struct {
+		ID       int
+		Password string
+		Internal string
+		Items    []int
+	}
+
+	d := newDumperT(t,
+		WithRedactFields("Password"),
+		WithExcludeFields("Internal"),
+		WithMaxItems(2),
+	)
+	out := d.DumpGoStr(Account{ID: 1, Password: "secret", Internal: "x", Items: []int{1, 2, 3}})
+	assertParsesAsGo(t, out)
+	assert.Contains(t, out, "// Password: <redacted>")
+	assert.NotContains(t, out, "secret")
+	assert.NotContains(t, out, "Internal")
+	assert.Contains(t, out, "// ... (truncated)")
+	assert.NotContains(t, out, "3,")
+
+	out = newDumperT(t, WithMaxDepth(1)).DumpGoStr(map[string][]int{"a": {1}})
+	assertParsesAsGo(t, out)
+	assert.Contains(t, out, `"a": nil, /* ... (max depth) */`)
+
+	out = newDumperT(t, WithMaxStringLen(3)).DumpGoStr("abcdef")
+	assert.Equal(t, `"abc" /* ... (truncated) */`, out)
+}
+
+func TestDumpGoWriters(t *testing.T) {
+	var buf bytes.Buffer
+	newDumperT(t, WithWriter(&buf)).DumpGo(1, "a")
+	assert.Equal(t, "1\n\"a\"\n", buf.String())
+
+	oldDefault := defaultDumper
+	defaultDumper = NewDumper(WithWriter(&buf))
+	defer func() { defaultDumper = oldDefault }()
+
+	buf.Reset()
+	DumpGo([]int{1})
+	assert.Equal(t, "[]int{\n\t1,\n}\n", buf.String())
+	assert.Equal(t, "2", DumpGoStr(2))
+}
+
+func TestFormatGoExprFallback(t *testing.T) {
+	assert.Equal(t, "not valid {", formatGoExpr("not valid {"))
+}
+
+func TestGoTimeLiteralZones(t *testing.T) {
+	local := time.Date(2020, time.May, 4, 0, 0, 0, 0, time.Local)
+	assert.Contains(t, newDumperT(t).DumpGoStr(local), "time.Local)")
+
+	zoned := time.Date(2020, time.May, 4, 0, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	assert.Contains(t, newDumperT(t).DumpGoStr(zoned), `time.FixedZone("EST", -18000))`)
+}
@@ -0,0 +1,180 @@
+package godump
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// HeaderInfo describes the call that printed a dump or diff, for formatting
+// its header line.
+type HeaderInfo struct {
+	// Kind is "dump" or "diff".
+	Kind string
+	// File and Line are the call site, with File relative to the working
+	// directory when possible.
+	File string
+	Line int
+	// Function is the calling function, such as "main.handler" or
+	// "server.(*API).Get".
+	Function string
+	// Goroutine is the id of the calling goroutine, or 0 when unknown. It is
+	// only looked up for header formats that name it and header funcs.
+	Goroutine uint64
+	// Time is when the header was printed, and Elapsed the time since the
+	// previous header printed by the same Dumper, or 0 for the first.
+	Time    time.Time
+	Elapsed time.Duration
+	// Label is the text set with [WithHeaderLabel].
+	Label string
+	// Hostname and PID identify the process.
+	Hostname string
+	PID      int
+}
+
+// HeaderFunc returns the text of a header line, which is printed after the
+// "<#dump // " or "<#diff // " marker.
+type HeaderFunc func(HeaderInfo) string
+
+// WithHeaderFormat sets the header text from a text/template executed with a
+// [HeaderInfo]. Headers that start with {{.File}}:{{.Line}} can still be read
+// by [Parse]. A template that fails to parse or execute prints the error in
+// place of the header.
+// @group Options
+//
+// Example: add the function, time and elapsed time to headers
+//
+//	// Default: "{{.File}}:{{.Line}}"
+//	d := godump.NewDumper(godump.WithHeaderFormat(
+//		`{{.File}}:{{.Line}} {{.Function}} at {{.Time.Format "15:04:05.000"}} (+{{.Elapsed}})`,
+//	))
+//	d.Dump("job started")
+//	// <#dump // main.go:14 main.main at 09:41:07.512 (+0s)
+//	// "job started" #string
+func WithHeaderFormat(format string) Option {
+	return func(d *Dumper) *Dumper {
+		tmpl, err := template.New("header").Parse(format)
+		if err != nil {
+			d.headerFunc = func(HeaderInfo) string { return "header format: " + err.Error() }
+			d.headerGoroutine = false
+			return d
+		}
+		d.headerGoroutine = strings.Contains(format, "Goroutine")
+		d.headerFunc = func(info HeaderInfo) string {
+			var sb strings.Builder
+			if err := tmpl.Execute(&sb, info); err != nil {
+				return "header format: " + err.Error()
+			}
+			return sb.String()
+		}
+		return d
+	}
+}
+
+// WithHeaderFunc sets a function that returns the header text from a
+// [HeaderInfo], for headers a template cannot express.
+// @group Options
+//
+// Example: tag headers with the host and process
+//
+//	d := godump.NewDumper(godump.WithHeaderFunc(func(h godump.HeaderInfo) string {
+//		return fmt.Sprintf("%s:%d %s/%d", h.File, h.Line, h.Hostname, h.PID)
+//	}))
+//	d.Dump("ready")
+//	// <#dump // main.go:15 web-1/4211
+//	// "ready" #string
+func WithHeaderFunc(fn HeaderFunc) Option {
+	return func(d *Dumper) *Dumper {
+		d.headerFunc = fn
+		d.headerGoroutine = true
+		return d
+	}
+}
+
+// WithHeaderLabel adds a label to the dump and diff headers, such as the
+// name of the component dumping. Header formats show it with {{.Label}}.
+// @group Options
+//
+// Example: label the dumps of a worker
+//
+//	d := godump.NewDumper(godump.WithHeaderLabel("billing"))
+//	d.Dump("charged")
+//	// <#dump // main.go:12 billing
+//	// "charged" #string
+func WithHeaderLabel(label string) Option {
+	return func(d *Dumper) *Dumper {
+		d.headerLabel = label
+		return d
+	}
+}
+
+// headerClock records when a Dumper last printed a header. Copies of a
+// Dumper share it.
+type headerClock struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+// tick returns the time since the previous tick, or 0 for the first.
+func (c *headerClock) tick(now time.Time) time.Duration {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var elapsed time.Duration
+	if !c.last.IsZero() {
+		elapsed = now.Sub(c.last)
+	}
+	c.last = now
+	return elapsed
+}
+
+// headerText returns the header text for a call site, after the "<#kind // "
+// marker.
+func (d *Dumper) headerText(kind, file string, line int, pc uintptr) string {
+	if d.headerFunc == nil {
+		text := relativePath(file) + ":" + strconv.Itoa(line) + d.goroutineTag()
+		if d.headerLabel != "" {
+			text += " " + d.headerLabel
+		}
+		return text
+	}
+
+	now := time.Now()
+	info := HeaderInfo{
+		Kind:     kind,
+		File:     relativePath(file),
+		Line:     line,
+		Time:     now,
+		Elapsed:  d.clock.tick(now),
+		Label:    d.headerLabel,
+		Hostname: hostname(),
+		PID:      os.Getpid(),
+	}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		name := fn.Name()
+		info.Function = name[strings.LastIndex(name, "/")+1:]
+	}
+	if d.headerGoroutine {
+		info.Goroutine, _ = goroutineID()
+	}
+	return d.headerFunc(info)
+}
+
+var (
+	hostnameOnce sync.Once
+	hostnameVal  string
+)
+
+// hostname returns the host name of the machine, looked up once.
+func hostname() string {
+	hostnameOnce.Do(func() {
+		hostnameVal, _ = os.Hostname()
+	})
+	return hostnameVal
+}
@@ -0,0 +1,110 @@
+package godump
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+func TestWithHeaderFormat(t *testing.T) {
+	id, _ := goroutineID()
+	host, _ := os.Hostname()
+
+	d := NewDumper(WithoutColor(), WithHeaderLabel("billing"), WithHeaderFormat(
+		"{{.Kind}} {{.File}}:{{.Line}} {{.Function}} g{{.Goroutine}} {{.Label}} {{.Hostname}}/{{.PID}}",
+	))
+	_, _, line, _ := runtime.Caller(0)
+	out := d.DumpStr(1)
+	want := fmt.Sprintf("<#dump // dump header_test.go:%d godump.TestWithHeaderFormat g%d billing %s/%d\n1 #int\n", line+1, id, host, os.Getpid())
+	assert.Equal(t, want, out)
+
+	// Diff headers use the same format.
+	_, _, line, _ = runtime.Caller(0)
+	out = d.DiffStr(1, 2)
+	assert.True(t, strings.HasPrefix(out, fmt.Sprintf("<#diff // diff header_test.go:%d godump.TestWithHeaderFormat g%d billing", line+1, id)))
+
+	// The goroutine is only looked up for formats that show it.
+	d = NewDumper(WithoutColor(), WithHeaderFormat("{{.File}}"))
+	assert.False(t, d.headerGoroutine)
+	d = NewDumper(WithoutColor(), WithHeaderFunc(func(HeaderInfo) string { return "" }), WithHeaderFormat("g{{.Goroutine}}"))
+	assert.True(t, d.headerGoroutine)
+	assert.Equal(t, fmt.Sprintf("<#dump // g%d\n1 #int\n", id), d.DumpStr(1))
+
+	out = NewDumper(WithoutColor(), WithHeaderFormat("{{.Nope}}")).DumpStr(1)
+	assert.Contains(t, out, "<#dump // header format: template: header:1:2: executing")
+
+	out = NewDumper(WithoutColor(), WithHeaderFormat("{{.File")).DumpStr(1)
+	assert.Contains(t, out, "<#dump // header format: template: header:1: unclosed action")
+}
+
+func TestHeaderElapsed(t *testing.T) {
+	var elapsed []time.Duration
+	var stamps []time.Time
+	d := NewDumper(WithoutColor(), WithWriter(io.Discard), WithHeaderFunc(func(h HeaderInfo) string {
+		elapsed = append(elapsed, h.Elapsed)
+		stamps = append(stamps, h.Time)
+		return h.File
+	}))
+
+	assert.Equal(t, "<#dump // header_test.go\n1 #int\n", d.DumpStr(1))
+	time.Sleep(time.Millisecond)
+	d.DumpStr(2)
+	// Copies made by DumpContext share the clock.
+	d.DumpContext(context.Background(), 3)
+	d.DiffStr(1, 2)
+
+	assert.Equal(t, time.Duration(0), elapsed[0])
+	for i := 1; i < len(elapsed); i++ {
+		assert.Equal(t, stamps[i].Sub(stamps[i-1]), elapsed[i])
+	}
+	assert.True(t, elapsed[1] >= time.Millisecond)
+
+	// Header formats are timed the same way.
+	d = NewDumper(WithoutColor(), WithHeaderFormat("{{.Elapsed.Nanoseconds}}"))
+	assert.Equal(t, "<#dump // 0\n1 #int\n", d.DumpStr(1))
+	time.Sleep(time.Millisecond)
+	out := d.DumpStr(1)
+	ns, err := strconv.ParseInt(strings.TrimPrefix(strings.SplitN(out, "\n", 2)[0], "<#dump // "), 10, 64)
+	assert.NoError(t, err)
+	assert.True(t, ns >= int64(time.Millisecond))
+
+	// Other dumpers keep their own clock.
+	NewDumper(WithHeaderFunc(func(h HeaderInfo) string {
+		assert.Equal(t, time.Duration(0), h.Elapsed)
+		return ""
+	})).DumpStr(1)
+}
+
+func TestWithHeaderLabel(t *testing.T) {
+	id, _ := goroutineID()
+	d := NewDumper(WithoutColor(), WithGoroutineID(), WithHeaderLabel("worker 7"))
+	_, _, line, _ := runtime.Caller(0)
+	out := d.DumpStr(1)
+	assert.Contains(t, out, fmt.Sprintf("header_test.go:%d [goroutine %d] worker 7\n", line+1, id))
+
+	dumps, err := Parse(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "header_test.go", dumps[0].File)
+	assert.Equal(t, line+1, dumps[0].Line)
+	assert.Equal(t, id, dumps[0].Goroutine)
+
+	// Parse reads custom headers that start with the location.
+	_, _, line, _ = runtime.Caller(0)
+	out = NewDumper(WithHeaderFormat("{{.File}}:{{.Line}} {{.Function}}")).DumpStr(1)
+	dumps, err = Parse(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "header_test.go", dumps[0].File)
+	assert.Equal(t, line+1, dumps[0].Line)
+
+	_, _, line, _ = runtime.Caller(0)
+	page := NewDumper(WithHeaderLabel("worker 7")).DumpHTMLPage(1)
+	assert.Contains(t, page, fmt.Sprintf("<title>godump · header_test.go:%d worker 7</title>", line+1))
+}
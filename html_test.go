@@ -0,0 +1,130 @@
+package godump
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+type markupStringer struct{}
+
+func (markupStringer) String() string { return `<i onmouseover="alert(1)">hi</i>` }
+
+type markupCell struct{ V int }
+
+// htmlCorpus holds values whose text would inject markup if printed raw.
+func htmlCorpus() []any {
+	type Tagged struct {
+		Name  string `godump:"name=<b>x</b>"`
+		Inner markupStringer
+	}
+	return []any{
+		"<script>alert(1)</script>",
+		"Tom & Jerry's \"show\"",
+		map[string]int{`"><img src=x onerror=alert(1)>`: 1},
+		Tagged{Name: "</pre><script>"},
+		markupStringer{},
+		errors.New("<b>boom</b>"),
+		[]byte("<script>&"),
+		make(chan<- int),
+		markupCell{V: 1},
+	}
+}
+
+// htmlCorpusOptions registers hooks that return markup in values and labels.
+func htmlCorpusOptions() []Option {
+	return []Option{
+		WithoutHeader(),
+		WithTypeFormatter(reflect.TypeOf(errors.New("")), func(v reflect.Value) string {
+			return "<err>" + v.Interface().(error).Error()
+		}),
+		WithTypeExpander(reflect.TypeOf(markupCell{}), func(v reflect.Value) []Child {
+			return []Child{{Label: "<th>", Value: "<td>"}, {Label: "ok", Value: 1}}
+		}),
+	}
+}
+
+var allowedHTMLTags = regexp.MustCompile(`<span style="[^"<>]*">|<span class="gd-[a-z-]+">|</span>|<div style='[^'<>]*'>|</div>|<pre style="[^"<>]*">|<pre class="godump">|</pre>`)
+
+// assertEscapedHTML fails when out contains markup other than the tags the
+// HTML renderer emits itself.
+func assertEscapedHTML(t *testing.T, out string) {
+	t.Helper()
+	text := allowedHTMLTags.ReplaceAllString(out, "")
+	if strings.Contains(text, "<") || strings.Contains(strings.ReplaceAll(text, "=>", ""), ">") {
+		t.Errorf("unescaped markup in HTML output:\n%s", out)
+	}
+}
+
+func TestDumpHTMLEscapesCorpus(t *testing.T) {
+	modes := map[string][]Option{
+		"color":   nil,
+		"nocolor": {WithoutColor()},
+		"compact": {WithCompact(200)},
+		"classes": {WithHTMLTheme("")},
+	}
+	for name, extra := range modes {
+		t.Run(name, func(t *testing.T) {
+			d := NewDumper(append(htmlCorpusOptions(), extra...)...)
+			out := d.DumpHTML(htmlCorpus()...)
+			assertEscapedHTML(t, out)
+
+			assert.Contains(t, out, "&lt;script&gt;alert(1)&lt;/script&gt;")
+			assert.Contains(t, out, "Tom &amp; Jerry&#39;s &#34;show&#34;")
+			assert.Contains(t, out, "&#34;&gt;&lt;img src=x onerror=alert(1)&gt;")
+			assert.Contains(t, out, "&lt;b&gt;x&lt;/b&gt;")
+			assert.Contains(t, out, "&lt;/pre&gt;&lt;script&gt;")
+			assert.Contains(t, out, "&lt;i onmouseover=&#34;alert(1)&#34;&gt;hi&lt;/i&gt;")
+			assert.Contains(t, out, "&lt;err&gt;&lt;b&gt;boom&lt;/b&gt;")
+			assert.Contains(t, out, "&lt;th&gt;")
+			assert.Contains(t, out, "&lt;td&gt;")
+			assert.Contains(t, out, "chan&lt;- int")
+		})
+	}
+}
+
+func TestDumpHTMLEscapesHexDump(t *testing.T) {
+	out := NewDumper(WithoutHeader()).DumpHTML([]byte("<a>&"))
+	assertEscapedHTML(t, out)
+	assert.Contains(t, out, ">&lt;</span>")
+	assert.Contains(t, out, ">&amp;</span>")
+
+	// Each part of the dump is colored once, not wrapped in an outer span.
+	assert.NotContains(t, out, "<span style=\"color:#80ff80\">([]uint8) (len=4 cap=4) {")
+	assert.Contains(t, stripHTMLSpans(out), "([]uint8) (len=4 cap=4) {\n")
+}
+
+func TestDiffHTMLEscapesCorpus(t *testing.T) {
+	for _, d := range []*Dumper{
+		NewDumper(htmlCorpusOptions()...),
+		NewDumper(append(htmlCorpusOptions(), WithoutColor())...),
+		NewDumper(append(htmlCorpusOptions(), WithHTMLTheme(""))...),
+	} {
+		out := d.DiffHTML(htmlCorpus(), append(htmlCorpus(), "<new>"))
+		assertEscapedHTML(t, out)
+		assert.Contains(t, out, "&lt;new&gt;")
+	}
+
+	// Differing types print their names through the escaper too.
+	out := DiffHTML(make(chan<- int), make(<-chan int))
+	assertEscapedHTML(t, out)
+	assert.Contains(t, out, "type: chan&lt;- int")
+	assert.Contains(t, out, "type: &lt;-chan int")
+}
+
+func TestVisibleWidthUnescapesHTML(t *testing.T) {
+	line := colorizeHTML(colorLime, `"<a> & b"`) + colorizeHTML(colorPlain, " => ")
+	h := NewDumper().htmlClone()
+	assert.Equal(t, 13, h.visibleWidth(line))
+	// Uncolored HTML has entities but no spans.
+	assert.Equal(t, 1, h.visibleWidth("&lt;"))
+	// Text output is measured as is.
+	assert.Equal(t, 4, NewDumper().visibleWidth("&lt;"))
+
+	out := NewDumper(append(htmlCorpusOptions(), WithoutColor())...).DumpHTML(markupCell{})
+	assert.Contains(t, out, "  &lt;th&gt; => &#34;&lt;td&gt;&#34; #string\n  ok   => 1 #int\n")
+}
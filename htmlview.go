@@ -0,0 +1,410 @@
+package godump
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// viewOpenDepth is the nesting depth up to which the HTML viewer starts with
+// nodes expanded.
+const viewOpenDepth = 3
+
+// DumpHTMLPage returns the values as a self-contained HTML document with an
+// interactive viewer: collapsible nodes, expand and collapse all, search over
+// keys and values, type badges, and click-to-copy of the path to a node.
+// Styles and scripts are inline, so the page needs no external assets.
+// @group HTML
+//
+// Example: build an interactive page
+//
+//	v := map[string][]int{"a": {1, 2}}
+//	page := godump.DumpHTMLPage(v)
+//	_ = page
+//	// <!DOCTYPE html> ... (interactive viewer)
+func DumpHTMLPage(vs ...any) string {
+	return defaultDumper.DumpHTMLPage(vs...)
+}
+
+// DumpHTMLPage returns the values as a self-contained HTML document with an
+// interactive viewer, rendered with the dumper's options.
+// @group HTML
+//
+// Example: interactive page with a custom dumper
+//
+//	d := godump.NewDumper(godump.WithMaxItems(50))
+//	v := map[string][]int{"a": {1, 2}}
+//	page := d.DumpHTMLPage(v)
+//	_ = page
+//	// <!DOCTYPE html> ... (interactive viewer)
+func (d *Dumper) DumpHTMLPage(vs ...any) string {
+	d = d.clone()
+	d.htmlClasses = true
+	d = d.htmlClone()
+
+	source := ""
+	if !d.disableHeader {
+		if file, line, pc := d.findCaller(d.skippedStackFrames); file != "" {
+			source = d.headerText("dump", file, line, pc)
+		}
+	}
+	title := "godump"
+	if source != "" {
+		title += " · " + source
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	sb.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	sb.WriteString("<style>\n" + d.currentTheme().Stylesheet() + d.htmlStylesheet + htmlViewCSS + "</style>\n</head>\n<body class=\"godump\">\n")
+	sb.WriteString(`<header class="gd-bar">`)
+	if source != "" {
+		sb.WriteString(`<span class="gd-source">` + html.EscapeString(source) + `</span>`)
+	}
+	sb.WriteString(`<input type="search" id="gd-search" placeholder="Search keys and values" autocomplete="off">`)
+	sb.WriteString(`<button type="button" id="gd-expand">Expand all</button>`)
+	sb.WriteString(`<button type="button" id="gd-collapse">Collapse all</button>`)
+	sb.WriteString(`<span id="gd-status"></span></header>` + "\n")
+	sb.WriteString(`<main id="gd-root" class="gd-tree">` + "\n")
+
+	nodes := d.inspectValues(vs)
+	for i, n := range nodes {
+		path := "$"
+		if len(nodes) > 1 {
+			path += strconv.Itoa(i)
+		}
+		d.writeViewNode(&sb, n, viewKey(path, "gd-name"), path, 0)
+		if n.BudgetExceeded {
+			sb.WriteString(`<div class="gd-row">` + d.colorize(RoleMuted, "... (budget exceeded)") + "</div>\n")
+		}
+	}
+
+	sb.WriteString("</main>\n<script>" + htmlViewJS + "</script>\n</body>\n</html>\n")
+	return sb.String()
+}
+
+// DumpHTMLFile writes the values as a standalone interactive HTML page to
+// path, as returned by DumpHTMLPage.
+// @group HTML
+//
+// Example: write a page to attach to a ticket
+//
+//	v := map[string]int{"a": 1}
+//	path := filepath.Join(os.TempDir(), "dump.html")
+//	if err := godump.DumpHTMLFile(path, v); err != nil {
+//		panic(err)
+//	}
+func DumpHTMLFile(path string, vs ...any) error {
+	return defaultDumper.DumpHTMLFile(path, vs...)
+}
+
+// DumpHTMLFile writes the values as a standalone interactive HTML page to
+// path, rendered with the dumper's options.
+// @group HTML
+//
+// Example: write a page with a custom dumper
+//
+//	d := godump.NewDumper(godump.WithRedactSensitive())
+//	v := map[string]string{"token": "secret"}
+//	path := filepath.Join(os.TempDir(), "dump.html")
+//	if err := d.DumpHTMLFile(path, v); err != nil {
+//		panic(err)
+//	}
+func (d *Dumper) DumpHTMLFile(path string, vs ...any) error {
+	return os.WriteFile(path, []byte(d.DumpHTMLPage(vs...)), 0o644)
+}
+
+// writeViewNode writes n as a row of the viewer, or as a collapsible node
+// with its children. label is the HTML of the key shown before the value.
+func (d *Dumper) writeViewNode(sb *strings.Builder, n *Node, label, path string, depth int) {
+	// Interfaces show their dynamic value in place.
+	for n.Kind == NodeInterface && !n.Redacted && !n.MaxDepth && len(n.Children) == 1 {
+		n = n.Children[0]
+	}
+
+	attrs := ` data-path="` + html.EscapeString(path) + `"`
+	if n.ID > 0 {
+		attrs += fmt.Sprintf(` id="gd-ref-%d"`, n.ID)
+	}
+
+	if !isViewContainer(n) {
+		sb.WriteString(`<div class="gd-row"` + attrs + `>` + label + ` <span class="gd-arrow">=&gt;</span> `)
+		sb.WriteString(d.viewLeaf(n) + "</div>\n")
+		return
+	}
+
+	open := ""
+	if depth < viewOpenDepth {
+		open = " open"
+	}
+	sb.WriteString(`<details class="gd-node"` + attrs + open + `><summary>` + label + ` <span class="gd-arrow">=&gt;</span> `)
+	sb.WriteString(d.viewSummary(n) + "</summary>\n")
+	sb.WriteString(`<div class="gd-children">` + "\n")
+	for _, c := range n.Children {
+		d.writeViewNode(sb, c, d.viewChildKey(n, c), viewChildPath(n, c, d.sanitize(c.Name), path), depth+1)
+	}
+	if n.Truncated && n.Kind != NodeError {
+		sb.WriteString(`<div class="gd-row">` + d.colorize(RoleMuted, "... (truncated)") + "</div>\n")
+	}
+	sb.WriteString("</div></details>\n")
+}
+
+// isViewContainer reports whether the viewer shows n as a collapsible node.
+func isViewContainer(n *Node) bool {
+	if n.Redacted || n.MaxDepth || n.Kind == NodeRef {
+		return false
+	}
+	switch n.Kind {
+	case NodeStruct, NodeMap, NodeList, NodeExpanded:
+		return true
+	case NodeError, NodeChan, NodeFunc:
+		return len(n.Children) > 0
+	default:
+		return false
+	}
+}
+
+// viewSummary returns the HTML shown on the line of a collapsible node.
+func (d *Dumper) viewSummary(n *Node) string {
+	var parts []string
+	if n.ID > 0 {
+		parts = append(parts, d.colorize(RoleRef, fmt.Sprintf("&%d", n.ID)))
+	}
+
+	switch n.Kind {
+	case NodeChan, NodeFunc:
+		// Render the header line alone; the children follow as rows.
+		head := *n
+		head.Children = nil
+		var w strings.Builder
+		if n.Kind == NodeChan {
+			d.printChan(&w, &head, 0)
+			return strings.Join(append(parts, `<span class="gd-val">`+w.String()+`</span>`), " ")
+		}
+		head.Type = ""
+		d.printFunc(&w, &head, 0)
+		parts = append(parts, `<span class="gd-val">`+strings.TrimSuffix(w.String(), " ")+`</span>`)
+	case NodeError:
+		parts = append(parts, `<span class="gd-val">`+d.quotedString(n.Value, n.Truncated)+`</span>`)
+	}
+
+	parts = append(parts, viewBadge(n.Type))
+	switch n.Kind {
+	case NodeStruct:
+		parts = append(parts, viewMeta(plural(len(n.Children), "field", "fields")))
+	case NodeMap:
+		parts = append(parts, viewMeta(plural(max0(n.Len, len(n.Children)), "entry", "entries")))
+	case NodeList:
+		// The address tag of a slice includes its length and capacity.
+		if !d.showAddresses {
+			meta := fmt.Sprintf("len=%d", n.Len)
+			if n.slice {
+				meta += fmt.Sprintf(" cap=%d", n.Cap)
+			}
+			parts = append(parts, viewMeta(meta))
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, " ")) + d.addressTag(n)
+}
+
+// viewLeaf returns the HTML of a value shown on a single row. Types of
+// strings, numbers and text move to a badge; other values render as in
+// DumpHTML.
+func (d *Dumper) viewLeaf(n *Node) string {
+	var w strings.Builder
+	switch {
+	case n.Redacted || n.MaxDepth:
+		d.printNode(&w, n, 0)
+	case n.Kind == NodeString || n.Kind == NodeScalar:
+		return viewValue(d.scalarString(n), n.Type) + d.addressTag(n)
+	case n.Kind == NodeText:
+		return viewValue(d.colorize(RoleText, d.sanitize(n.Value)), n.Type)
+	case n.Kind == NodeError:
+		return viewValue(d.quotedString(n.Value, n.Truncated), n.Type)
+	case n.Kind == NodeRef && n.Ref != 0:
+		d.printNode(&w, n, 0)
+		return fmt.Sprintf(`<a class="gd-val gd-link" href="#gd-ref-%d">%s</a>`, n.Ref, w.String())
+	default:
+		d.printNode(&w, n, 0)
+	}
+	return `<span class="gd-val">` + w.String() + `</span>`
+}
+
+// viewValue returns the HTML of a value followed by the badge for its type.
+func viewValue(value, typeStr string) string {
+	if typeStr == "" {
+		return `<span class="gd-val">` + value + `</span>`
+	}
+	return `<span class="gd-val">` + value + `</span> ` + viewBadge(typeStr)
+}
+
+// viewChildKey returns the HTML of the key of child c in parent n, styled as
+// the text dump styles it.
+func (d *Dumper) viewChildKey(n, c *Node) string {
+	name := d.sanitize(c.Name)
+	switch n.Kind {
+	case NodeStruct:
+		if c.Unexported {
+			return viewKey(name, "gd-name gd-field gd-private")
+		}
+		return viewKey(name, "gd-name gd-field")
+	case NodeList, NodeChan:
+		return viewKey(name, "gd-name gd-index")
+	default:
+		return viewKey(name, "gd-name gd-key")
+	}
+}
+
+// viewKey returns a clickable key span. name must already be sanitized.
+func viewKey(name, class string) string {
+	return `<span class="` + class + `" title="Copy path">` + html.EscapeString(name) + `</span>`
+}
+
+// viewChildPath returns the path to child c of n, in Go selector and index
+// syntax, such as $.Items[0] or $.Meta["id"]. name is the sanitized name of
+// c; string keys are quoted from the raw key instead, since quoting escapes
+// them already.
+func viewChildPath(n, c *Node, name, path string) string {
+	switch {
+	case n.Kind == NodeList || n.Kind == NodeChan:
+		return path + "[" + name + "]"
+	case n.Kind == NodeMap && c.Key != nil && c.Key.Kind == NodeString:
+		return path + "[" + strconv.Quote(c.Name) + "]"
+	case n.Kind == NodeMap:
+		return path + "[" + name + "]"
+	default:
+		return path + "." + name
+	}
+}
+
+// viewBadge returns the type badge for typeStr, or nothing for an empty type.
+func viewBadge(typeStr string) string {
+	if typeStr == "" {
+		return ""
+	}
+	return `<span class="gd-badge">` + html.EscapeString(typeStr) + `</span>`
+}
+
+// viewMeta returns secondary details shown after a node's type.
+func viewMeta(s string) string {
+	return `<span class="gd-count">` + html.EscapeString(s) + `</span>`
+}
+
+// plural formats a count with the singular or plural noun.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return strconv.Itoa(n) + " " + many
+}
+
+// max0 returns the larger of two counts.
+func max0(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+const htmlViewCSS = `
+body { margin: 0; background: var(--gd-bg); color: var(--gd-fg); font: 13px/1.5 ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+.gd-bar { position: sticky; top: 0; display: flex; gap: 8px; align-items: center; padding: 8px 12px; background: var(--gd-bg); border-bottom: 1px solid rgba(128, 128, 128, 0.4); }
+.gd-source { color: var(--gd-muted, #999); margin-right: auto; }
+.gd-bar input { width: 240px; padding: 3px 6px; background: var(--gd-bg); color: var(--gd-fg); border: 1px solid rgba(128, 128, 128, 0.5); border-radius: 4px; font: inherit; }
+.gd-bar button { padding: 3px 8px; background: rgba(128, 128, 128, 0.15); color: var(--gd-fg); border: 1px solid rgba(128, 128, 128, 0.5); border-radius: 4px; font: inherit; cursor: pointer; }
+.gd-bar button:hover { background: rgba(128, 128, 128, 0.3); }
+#gd-status { color: var(--gd-muted, #999); min-width: 8em; }
+.gd-tree { padding: 8px 12px; }
+.gd-tree details > summary { cursor: pointer; list-style: none; }
+.gd-tree details > summary::-webkit-details-marker { display: none; }
+.gd-tree details > summary::before { content: "▸"; display: inline-block; width: 1em; color: var(--gd-muted, #999); }
+.gd-tree details[open] > summary::before { content: "▾"; }
+.gd-children { margin-left: 0.6em; padding-left: 1em; border-left: 1px dotted rgba(128, 128, 128, 0.4); }
+.gd-row { padding-left: 1em; white-space: pre-wrap; word-break: break-all; }
+.gd-name { cursor: copy; }
+.gd-name:hover { text-decoration: underline; }
+.gd-private { opacity: 0.75; }
+.gd-private::before { content: "-"; color: var(--gd-visibility); }
+.gd-arrow { color: var(--gd-muted, #999); }
+.gd-val { white-space: pre-wrap; }
+.gd-link { text-decoration: none; }
+.gd-badge { display: inline-block; padding: 0 6px; margin-left: 4px; border-radius: 8px; background: rgba(128, 128, 128, 0.12); border: 1px solid rgba(128, 128, 128, 0.4); color: var(--gd-type, #999); font-size: 11px; line-height: 16px; }
+.gd-count { color: var(--gd-muted, #999); font-size: 11px; }
+.gd-hit { background: rgba(255, 180, 0, 0.3); outline: 1px solid #ffb400; border-radius: 2px; }
+`
+
+const htmlViewJS = `
+(function () {
+  var root = document.getElementById("gd-root");
+  var search = document.getElementById("gd-search");
+  var status = document.getElementById("gd-status");
+
+  function all(selector) {
+    return Array.prototype.slice.call(root.querySelectorAll(selector));
+  }
+  function reveal(el) {
+    for (var p = el.parentElement; p && p !== root; p = p.parentElement) {
+      if (p.tagName === "DETAILS") p.open = true;
+    }
+  }
+  function setOpen(open) {
+    all("details").forEach(function (d) { d.open = open; });
+  }
+  function copy(text) {
+    function done() { status.textContent = "Copied " + text; }
+    function fallback() {
+      var area = document.createElement("textarea");
+      area.value = text;
+      document.body.appendChild(area);
+      area.select();
+      try { document.execCommand("copy"); done(); } catch (e) { status.textContent = text; }
+      document.body.removeChild(area);
+    }
+    if (navigator.clipboard && window.isSecureContext) {
+      navigator.clipboard.writeText(text).then(done, fallback);
+    } else {
+      fallback();
+    }
+  }
+
+  document.getElementById("gd-expand").addEventListener("click", function () { setOpen(true); });
+  document.getElementById("gd-collapse").addEventListener("click", function () { setOpen(false); });
+
+  search.addEventListener("input", function () {
+    var query = search.value.toLowerCase();
+    var hits = 0;
+    all(".gd-hit").forEach(function (el) { el.classList.remove("gd-hit"); });
+    if (query) {
+      all(".gd-name, .gd-val, .gd-badge").forEach(function (el) {
+        if (el.textContent.toLowerCase().indexOf(query) >= 0) {
+          el.classList.add("gd-hit");
+          reveal(el);
+          hits++;
+        }
+      });
+      var first = root.querySelector(".gd-hit");
+      if (first) first.scrollIntoView({ block: "nearest" });
+    }
+    status.textContent = query ? hits + (hits === 1 ? " match" : " matches") : "";
+  });
+
+  root.addEventListener("click", function (e) {
+    var key = e.target.closest(".gd-name");
+    if (key) {
+      e.preventDefault();
+      copy(key.closest("[data-path]").getAttribute("data-path"));
+      return;
+    }
+    var ref = e.target.closest(".gd-link");
+    if (ref) {
+      var target = document.getElementById(ref.getAttribute("href").slice(1));
+      if (target) {
+        reveal(target);
+        target.open = true;
+      }
+    }
+  });
+})();
+`
@@ -0,0 +1,94 @@
+package godump
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+type viewOrder struct {
+	ID    int
+	Lines []string
+	Meta  map[string]any
+	note  string
+	Self  *viewOrder
+}
+
+// viewTree returns the tree section of a viewer page.
+func viewTree(t *testing.T, page string) string {
+	t.Helper()
+	start := strings.Index(page, `<main id="gd-root"`)
+	end := strings.Index(page, "</main>")
+	if start < 0 || end < start {
+		t.Fatalf("page has no tree:\n%s", page)
+	}
+	return page[start:end]
+}
+
+func TestDumpHTMLPageTree(t *testing.T) {
+	o := &viewOrder{ID: 7, Lines: []string{"a", "b"}, Meta: map[string]any{"k": 1}, note: "n"}
+	o.Self = o
+	page := NewDumper(WithoutColor()).DumpHTMLPage(o)
+
+	assert.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"))
+	assert.Contains(t, page, "htmlview_test.go:")
+	assert.Contains(t, page, `<input type="search" id="gd-search"`)
+	assert.Contains(t, page, `id="gd-expand"`)
+	assert.NotContains(t, page, "src=")
+	assert.NotContains(t, page, "href=\"http")
+
+	tree := viewTree(t, page)
+	for _, path := range []string{`$`, `$.ID`, `$.Lines`, `$.Lines[1]`, `$.Meta[&#34;k&#34;]`, `$.note`, `$.Self`} {
+		assert.Contains(t, tree, `data-path="`+path+`"`)
+	}
+	assert.Contains(t, tree, `<details class="gd-node" data-path="$" id="gd-ref-1" open>`)
+	assert.Contains(t, tree, `<a class="gd-val gd-link" href="#gd-ref-1">`)
+	assert.Contains(t, tree, `<span class="gd-badge">*godump.viewOrder</span> <span class="gd-count">5 fields</span>`)
+	assert.Contains(t, tree, `<span class="gd-val">&#34;a&#34;</span> <span class="gd-badge">string</span>`)
+	assert.Contains(t, tree, `<span class="gd-name gd-field gd-private" title="Copy path">note</span>`)
+
+	page = NewDumper(WithoutHeader(), WithMaxItems(1)).DumpHTMLPage([]int{1, 2}, "x")
+	tree = viewTree(t, page)
+	assert.NotContains(t, page, "htmlview_test.go")
+	assert.Contains(t, tree, `data-path="$0[0]"`)
+	assert.Contains(t, tree, `data-path="$1"`)
+	assert.Contains(t, tree, "... (truncated)")
+
+	// Keys are escaped once, by quoting.
+	tree = viewTree(t, NewDumper().DumpHTMLPage(map[string]int{"a\tb\u202e": 1}))
+	assert.Contains(t, tree, `data-path="$[&#34;a\tb\u202e&#34;]"`)
+}
+
+var viewerTags = regexp.MustCompile(`<(main|span|div|details|summary|a)( (class|title|data-path|id|href|style)="[^"<>]*"| open)*>|</(span|div|details|summary|a)>`)
+
+func TestDumpHTMLPageEscapesCorpus(t *testing.T) {
+	for name, extra := range map[string][]Option{"color": nil, "nocolor": {WithoutColor()}} {
+		t.Run(name, func(t *testing.T) {
+			d := NewDumper(append(htmlCorpusOptions(), extra...)...)
+			tree := viewTree(t, d.DumpHTMLPage(htmlCorpus()...))
+			text := viewerTags.ReplaceAllString(tree, "")
+			if strings.Contains(text, "<") || strings.Contains(text, ">") {
+				t.Errorf("unescaped markup in viewer:\n%s", tree)
+			}
+			assert.Contains(t, tree, "&lt;script&gt;alert(1)&lt;/script&gt;")
+			assert.Contains(t, tree, `data-path="$2[&#34;\&#34;&gt;&lt;img src=x onerror=alert(1)&gt;&#34;]"`)
+		})
+	}
+}
+
+func TestDumpHTMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.html")
+	d := NewDumper(WithoutHeader())
+	assert.NoError(t, d.DumpHTMLFile(path, map[string]int{"a": 1}))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, d.DumpHTMLPage(map[string]int{"a": 1}), string(data))
+
+	err = d.DumpHTMLFile(filepath.Join(t.TempDir(), "missing", "dump.html"), 1)
+	assert.True(t, err != nil)
+}
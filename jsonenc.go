@@ -0,0 +1,682 @@
+package godump
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// JSONPlain renders values as plain JSON, like encoding/json (the default).
+	JSONPlain JSONMode = iota
+	// JSONTyped wraps every value in an object that records its Go type.
+	JSONTyped
+)
+
+// JSONMode selects the shape of the documents written by DumpJSON.
+//
+// In [JSONPlain] mode values are written as encoding/json writes them: struct
+// fields follow its rules for json tags, omitempty and embedded structs, and
+// values with a MarshalJSON or MarshalText method are written by it. Field
+// filters, redaction and limits still apply. Limits are marked with the
+// strings "... (max depth)" and "... (truncated)", redacted fields with
+// "<redacted>", and channels, funcs and unsafe pointers with a description.
+// A value reached more than once is written in full the first time only,
+// and as {"$ref": id} after that; objects written in full carry the id as
+// "$id".
+//
+// In [JSONTyped] mode every value is an object with a "type" member and one of
+// "value", "fields" (structs and expanded types), "entries" (maps, as a list of
+// key/value pairs), "items" (slices and arrays, with "len" and "cap") or
+// "base64" (byte slices). Values reached more than once get an "id", and
+// later occurrences are written as {"type": ..., "ref": id}. With
+// [WithAddresses], a value whose memory overlaps an earlier one has an
+// "alias" holding that value's id. Limits are marked with "truncated": true,
+// "maxDepth": true and "redacted": true.
+type JSONMode int
+
+// WithJSONMode sets the document shape used by DumpJSON and DumpJSONStr.
+// @group Options
+//
+// Example: typed JSON
+//
+//	// Default: JSONPlain
+//	type User struct{ Name string }
+//	d := godump.NewDumper(godump.WithJSONMode(godump.JSONTyped))
+//	d.DumpJSON(&User{Name: "Alice"})
+//	// {
+//	//   "type": "*main.User",
+//	//   "fields": {
+//	//     "Name": {
+//	//       "type": "string",
+//	//       "value": "Alice"
+//	//     }
+//	//   }
+//	// }
+func WithJSONMode(mode JSONMode) Option {
+	return func(d *Dumper) *Dumper {
+		d.jsonMode = mode
+		return d
+	}
+}
+
+// jsonObject is a JSON object that keeps its members in insertion order.
+type jsonObject []jsonMember
+
+// jsonMember is a single key and value of a jsonObject.
+type jsonMember struct {
+	key   string
+	value any
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encodeJSON(&buf, m.key, ""); err != nil {
+			return nil, err
+		}
+		buf.WriteByte(':')
+		if err := encodeJSON(&buf, m.value, ""); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// encodeJSON writes v to buf without HTML escaping, so values such as
+// "<redacted>" stay readable.
+func encodeJSON(buf *bytes.Buffer, v any, indent string) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	buf.Truncate(buf.Len() - 1) // drop the newline Encode appends
+	return nil
+}
+
+// jsonDocument encodes the values as one document: the value itself, or an
+// array when there is more than one.
+func (d *Dumper) jsonDocument(vs []any) ([]byte, error) {
+	j := d.clone()
+	j.jsonPlain = d.jsonMode == JSONPlain
+	nodes := j.inspectValues(vs)
+
+	var data any
+	if len(nodes) == 1 {
+		data = d.jsonTopLevel(nodes[0])
+	} else {
+		items := make([]any, len(nodes))
+		for i, n := range nodes {
+			items[i] = d.jsonTopLevel(n)
+		}
+		data = items
+	}
+
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, data, strings.Repeat(" ", indentWidth)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonTopLevel converts a top-level node like jsonNode, marking the value
+// during which the output budget ran out: typed nodes get "budgetExceeded",
+// plain objects a "$truncated" member and plain arrays a last item.
+func (d *Dumper) jsonTopLevel(n *Node) any {
+	data := d.jsonNode(n)
+	if !n.BudgetExceeded {
+		return data
+	}
+	switch v := data.(type) {
+	case jsonObject:
+		if d.jsonMode == JSONTyped {
+			return append(v, jsonMember{"budgetExceeded", true})
+		}
+		return append(v, jsonMember{"$truncated", "budget exceeded"})
+	case []any:
+		return append(v, "... (budget exceeded)")
+	}
+	return data
+}
+
+// jsonNode converts n to a value encoding/json writes as the document for n.
+func (d *Dumper) jsonNode(n *Node) any {
+	switch {
+	case n.Kind == NodeNil:
+		return d.jsonScalar(n.Type, nil)
+	case n.rawJSON != nil:
+		return n.rawJSON
+	case n.Kind == NodeRef:
+		switch {
+		case d.jsonMode == JSONTyped && n.Ref == 0:
+			return jsonObject{{"type", n.Type}, {"cycle", true}}
+		case d.jsonMode == JSONTyped:
+			return jsonObject{{"type", n.Type}, {"ref", n.Ref}}
+		case n.Ref == 0:
+			return "... (cycle)"
+		}
+		return jsonObject{{"$ref", n.Ref}}
+	case n.Redacted:
+		return d.jsonMarker(n.Type, "redacted", "<redacted>")
+	case n.MaxDepth && n.Kind != NodeError && n.Kind != NodeChan && n.Kind != NodeFunc:
+		return d.jsonMarker(n.Type, "maxDepth", "... (max depth)")
+	case n.Kind == NodeInvalid:
+		return nil
+	}
+
+	node := jsonObject{{"type", n.Type}}
+	if n.ID > 0 {
+		node = append(node, jsonMember{"id", n.ID})
+	}
+	if n.Alias > 0 {
+		node = append(node, jsonMember{"alias", n.Alias})
+	}
+
+	switch n.Kind {
+	case NodeText, NodeUnsafePointer, NodeFunc:
+		// Nil functions have no name and closure captures no value, so
+		// both show their type.
+		if n.Value == "" {
+			return d.jsonScalar(n.Type, n.Type)
+		}
+		return d.jsonScalar(n.Type, n.Value)
+	case NodeError:
+		return d.jsonError(n)
+	case NodeChan:
+		if d.jsonMode == JSONTyped {
+			return jsonObject{{"type", n.Type}, {"len", n.Len}, {"cap", n.Cap}}
+		}
+		return fmt.Sprintf("%s (len=%d cap=%d)", n.Type, n.Len, n.Cap)
+	case NodeInterface:
+		inner := d.jsonNode(n.Children[0])
+		obj, ok := inner.(jsonObject)
+		switch {
+		case !ok || n.ID == 0:
+			return inner
+		case d.jsonMode == JSONPlain:
+			return withJSONID(n, obj)
+		}
+		// Keep the pointer's anchor on the dynamic value's node.
+		return append(jsonObject{obj[0], {"id", n.ID}}, obj[1:]...)
+	case NodeStruct:
+		fields := d.jsonStruct(n)
+		if d.jsonMode == JSONPlain {
+			if n.Truncated {
+				fields = append(fields, jsonMember{"...", "(truncated)"})
+			}
+			return withJSONID(n, fields)
+		}
+		node = append(node, jsonMember{"fields", fields})
+		if n.Truncated {
+			node = append(node, jsonMember{"truncated", true})
+		}
+		return node
+	case NodeExpanded:
+		return d.jsonChildren(n)
+	case NodeMap:
+		return d.jsonMap(node, n)
+	case NodeBytes:
+		s := base64.StdEncoding.EncodeToString(n.Bytes)
+		if d.jsonMode == JSONPlain {
+			return s
+		}
+		return append(node, jsonMember{"len", n.Len}, jsonMember{"cap", n.Cap}, jsonMember{"base64", s})
+	case NodeList:
+		return d.jsonList(node, n)
+	case NodeString:
+		s := n.Value
+		if d.jsonMode == JSONPlain {
+			if n.Truncated {
+				s += "…"
+			}
+			return s
+		}
+		node = append(node, jsonMember{"value", s})
+		if n.Truncated {
+			node = append(node, jsonMember{"truncated", true})
+		}
+		return node
+	default:
+		if n.Raw == nil {
+			return d.jsonScalar(n.Type, n.Value)
+		}
+		return d.jsonScalar(n.Type, jsonScalarValue(n.Raw))
+	}
+}
+
+// withJSONID marks a plain object that is referenced elsewhere in the
+// document with its id, which the references hold as "$ref".
+func withJSONID(n *Node, obj jsonObject) jsonObject {
+	if n.ID == 0 {
+		return obj
+	}
+	return append(jsonObject{{"$id", n.ID}}, obj...)
+}
+
+// jsonScalar returns value as is in plain mode, or wrapped with its type.
+func (d *Dumper) jsonScalar(typeStr string, value any) any {
+	if d.jsonMode == JSONPlain {
+		return value
+	}
+	return jsonObject{{"type", typeStr}, {"value", value}}
+}
+
+// jsonMarker returns the plain marker string, or a typed node with flag set.
+func (d *Dumper) jsonMarker(typeStr, flag, plain string) any {
+	if d.jsonMode == JSONPlain {
+		return plain
+	}
+	return jsonObject{{"type", typeStr}, {flag, true}}
+}
+
+// jsonStruct returns the fields of a struct node, using json tag names in
+// plain mode.
+func (d *Dumper) jsonStruct(n *Node) jsonObject {
+	fields := jsonObject{}
+	for _, f := range n.Children {
+		name := f.Name
+		if d.jsonMode == JSONPlain {
+			tagName, skip := jsonTagName(f.Tag)
+			if skip {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		fields = append(fields, jsonMember{name, d.jsonNode(f)})
+	}
+	return fields
+}
+
+// jsonMap returns a map as an object keyed by the printed keys in plain mode,
+// or as a list of typed key/value entries.
+func (d *Dumper) jsonMap(node jsonObject, n *Node) any {
+	if d.jsonMode == JSONPlain {
+		plain := jsonObject{}
+		for _, e := range n.Children {
+			plain = append(plain, jsonMember{e.Name, d.jsonNode(e)})
+		}
+		if n.Truncated {
+			plain = append(plain, jsonMember{"...", "(truncated)"})
+		}
+		return plain
+	}
+
+	entries := []any{}
+	for _, e := range n.Children {
+		entries = append(entries, jsonObject{{"key", d.jsonNode(e.Key)}, {"value", d.jsonNode(e)}})
+	}
+	node = append(node, jsonMember{"len", n.Len}, jsonMember{"entries", entries})
+	if n.Truncated {
+		node = append(node, jsonMember{"truncated", true})
+	}
+	return node
+}
+
+// jsonList returns a slice or array node as an array.
+func (d *Dumper) jsonList(node jsonObject, n *Node) any {
+	items := []any{}
+	for _, e := range n.Children {
+		items = append(items, d.jsonNode(e))
+	}
+
+	if d.jsonMode == JSONPlain {
+		if n.Truncated {
+			items = append(items, "... (truncated)")
+		}
+		return items
+	}
+	node = append(node, jsonMember{"len", n.Len})
+	if n.slice {
+		node = append(node, jsonMember{"cap", n.Cap})
+	}
+	node = append(node, jsonMember{"items", items})
+	if n.Truncated {
+		node = append(node, jsonMember{"truncated", true})
+	}
+	return node
+}
+
+// jsonChildren returns the children of an expanded type as an object keyed by
+// their labels.
+func (d *Dumper) jsonChildren(n *Node) any {
+	fields := jsonObject{}
+	for _, child := range n.Children {
+		fields = append(fields, jsonMember{child.Name, d.jsonNode(child)})
+	}
+
+	if d.jsonMode == JSONPlain {
+		if n.Truncated {
+			fields = append(fields, jsonMember{"...", "(truncated)"})
+		}
+		return withJSONID(n, fields)
+	}
+	node := jsonObject{{"type", n.Type}, {"fields", fields}}
+	if n.Truncated {
+		node = append(node, jsonMember{"truncated", true})
+	}
+	return node
+}
+
+// jsonError returns an error as its message. Typed nodes also list the errors
+// it wraps.
+func (d *Dumper) jsonError(n *Node) any {
+	if d.jsonMode == JSONPlain {
+		return n.Value
+	}
+	node := jsonObject{{"type", n.Type}, {"value", n.Value}}
+	if n.Truncated {
+		node = append(node, jsonMember{"truncated", true})
+	}
+	if n.MaxDepth {
+		return append(node, jsonMember{"maxDepth", true})
+	}
+	for _, child := range n.Children {
+		if child.Name != "Unwrap" {
+			continue
+		}
+		wrapped := []*Node{child}
+		if child.Kind == NodeList {
+			wrapped = child.Children
+		}
+		unwrap := make([]any, len(wrapped))
+		for i, inner := range wrapped {
+			unwrap[i] = d.jsonNode(inner)
+		}
+		node = append(node, jsonMember{"unwrap", unwrap})
+	}
+	return node
+}
+
+// jsonScalarValue returns the raw value of a bool or number as a value
+// encoding/json writes exactly. Values JSON cannot represent, such as NaN and
+// complex numbers, become strings.
+func jsonScalarValue(raw any) any {
+	switch x := raw.(type) {
+	case int64:
+		return json.Number(strconv.FormatInt(x, 10))
+	case uint64:
+		return json.Number(strconv.FormatUint(x, 10))
+	case float32:
+		return jsonFloat(float64(x), 32)
+	case float64:
+		return jsonFloat(x, 64)
+	case complex64:
+		return strconv.FormatComplex(complex128(x), 'g', -1, 64)
+	case complex128:
+		return strconv.FormatComplex(x, 'g', -1, 128)
+	default:
+		return raw
+	}
+}
+
+// jsonFloat formats f with the shortest representation for its bit size.
+func jsonFloat(f float64, bits int) any {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, bits))
+}
+
+// jsonTagName returns the name given to a field by its json struct tag, and
+// whether the tag omits the field.
+func jsonTagName(structTag reflect.StructTag) (string, bool) {
+	tag, ok := structTag.Lookup("json")
+	if !ok {
+		return "", false
+	}
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, false
+}
+
+// jsonField is a field encoding/json writes for a struct type. index is the
+// path to it through embedded structs.
+type jsonField struct {
+	name      string
+	index     []int
+	tagged    bool
+	omitEmpty bool
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// isJSONMarshaler reports whether encoding/json writes values of type t with
+// their own MarshalJSON or MarshalText method.
+func isJSONMarshaler(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
+
+// jsonFieldsOf lists the fields encoding/json writes for struct type t, in
+// the order it writes them. Fields of embedded structs without a json name
+// are promoted, and of several fields with the same name the shallowest
+// wins, or the tagged one among equally shallow fields. Otherwise none does.
+func jsonFieldsOf(t reflect.Type) []jsonField {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var fields []jsonField
+	visited := map[reflect.Type]bool{}
+	for level := []embedded{{typ: t}}; len(level) > 0; {
+		var next []embedded
+		for _, e := range level {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				ft := sf.Type
+				if sf.Anonymous && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if !sf.IsExported() && !(sf.Anonymous && ft.Kind() == reflect.Struct) {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(append([]int(nil), e.index...), i)
+				if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					next = append(next, embedded{typ: ft, index: index})
+					continue
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				f := jsonField{name: name, index: index, tagged: name != ""}
+				if name == "" {
+					f.name = sf.Name
+				}
+				for _, opt := range strings.Split(opts, ",") {
+					f.omitEmpty = f.omitEmpty || opt == "omitempty"
+				}
+				fields = append(fields, f)
+			}
+		}
+		level = next
+	}
+
+	// Keep the dominant field of each name, in index order.
+	byName := map[string][]jsonField{}
+	for _, f := range fields {
+		byName[f.name] = append(byName[f.name], f)
+	}
+	kept := fields[:0]
+	for _, f := range fields {
+		// Depth and tagging tell the dominant field apart from the others.
+		if dominant, ok := dominantJSONField(byName[f.name]); ok && len(dominant.index) == len(f.index) && dominant.tagged == f.tagged {
+			kept = append(kept, f)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		a, b := kept[i].index, kept[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return kept
+}
+
+// dominantJSONField returns the field encoding/json writes among fields of
+// the same name, if any.
+func dominantJSONField(fields []jsonField) (jsonField, bool) {
+	depth := len(fields[0].index)
+	for _, f := range fields[1:] {
+		if len(f.index) < depth {
+			depth = len(f.index)
+		}
+	}
+	var found []jsonField
+	var tagged []jsonField
+	for _, f := range fields {
+		if len(f.index) != depth {
+			continue
+		}
+		found = append(found, f)
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	switch {
+	case len(found) == 1:
+		return found[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	}
+	return jsonField{}, false
+}
+
+// inspectJSONFields adds the fields encoding/json writes for struct v to n,
+// under their JSON names. Field filters, redaction and tags still apply.
+func (d *Dumper) inspectJSONFields(n *Node, v reflect.Value, depth int, state *dumpState) {
+	for _, jf := range d.plan(v.Type()).jsonFields {
+		owner, ok := embeddedOwner(v, jf.index)
+		if !ok {
+			continue
+		}
+		f, ok := d.plan(owner.Type()).field(jf.index[len(jf.index)-1])
+		if !ok || (jf.omitEmpty && isEmptyJSONValue(owner.Field(f.index))) {
+			continue
+		}
+		if !state.budget.allows() {
+			n.Truncated = true
+			break
+		}
+		child := d.inspectField(owner, f, depth, state)
+		child.Name = jf.name
+		n.Children = append(n.Children, child)
+	}
+}
+
+// embeddedOwner follows index through the embedded structs of v to the
+// struct holding the last field. It reports false when an embedded pointer
+// on the way is nil.
+func embeddedOwner(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index[:len(index)-1] {
+		v = v.Field(i)
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+	}
+	return v, true
+}
+
+// isEmptyJSONValue reports whether an omitempty field leaves v out, as
+// encoding/json decides.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// inspectMarshaler returns the node of a value that plain JSON writes with
+// its MarshalJSON or MarshalText method, as encoding/json does, or nil.
+func (d *Dumper) inspectMarshaler(v reflect.Value, typeStr string) *Node {
+	if !d.jsonPlain || !d.plan(v.Type()).marshaler {
+		return nil
+	}
+	v = forceExported(v)
+	if !isJSONMarshaler(v.Type()) {
+		// The method has a pointer receiver.
+		if !v.CanAddr() {
+			return nil
+		}
+		v = v.Addr()
+	}
+	if !v.CanInterface() {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, v.Interface(), ""); err != nil {
+		return nil
+	}
+	return &Node{Kind: NodeText, Type: typeStr, Value: buf.String(), rawJSON: buf.Bytes()}
+}
+
+// jsonMapKey returns the name encoding/json gives a map key that is not a
+// string but has a MarshalText method.
+func (d *Dumper) jsonMapKey(key reflect.Value) (string, bool) {
+	if !d.jsonPlain || key.Kind() == reflect.String || !key.CanInterface() {
+		return "", false
+	}
+	m, ok := key.Interface().(encoding.TextMarshaler)
+	if !ok {
+		return "", false
+	}
+	text, err := m.MarshalText()
+	return string(text), err == nil
+}
+
+// truncateRunes cuts s to at most n runes and reports whether it did.
+func truncateRunes(s string, n int) (string, bool) {
+	if utf8.RuneCountInString(s) <= n {
+		return s, false
+	}
+	return string([]rune(s)[:n]), true
+}
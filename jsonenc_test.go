@@ -0,0 +1,240 @@
+package godump
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+type jsonAccount struct {
+	ID       int    `json:"id"`
+	Email    string `json:"email,omitempty"`
+	Password string
+	Internal string `json:"-"`
+	notes    []string
+	Next     *jsonAccount
+}
+
+func TestDumpJSONPlainHonorsOptions(t *testing.T) {
+	acct := &jsonAccount{ID: 1, Email: "a@example.com", Password: "hunter2", Internal: "x", notes: []string{"a", "b", "c"}}
+	acct.Next = acct
+	d := NewDumper(WithRedactFields("Password"), WithMaxItems(2))
+
+	assert.JSONEq(t, `{
+		"$id": 1,
+		"id": 1,
+		"email": "a@example.com",
+		"Password": "<redacted>",
+		"Next": {"$ref": 1}
+	}`, d.DumpJSONStr(acct))
+
+	d = NewDumper(WithExcludeFields("Next"), WithMaxStringLen(3))
+	assert.JSONEq(t, `{"id": 1, "email": "a@e…", "Password": "hun…"}`, d.DumpJSONStr(acct))
+}
+
+func TestDumpJSONPlainLimitsAndKinds(t *testing.T) {
+	v := map[string]any{
+		"nested": map[string]any{"deeper": map[string]int{"x": 1}},
+		"nan":    math.NaN(),
+		"inf":    math.Inf(-1),
+		"c":      complex(1, 2),
+		"bytes":  []byte("hi"),
+		"big":    uint64(math.MaxUint64),
+		"ptr":    reflect.ValueOf(new(int)).UnsafePointer(),
+	}
+	var got map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(NewDumper(WithMaxDepth(2)).DumpJSONStr(v)), &got))
+
+	assert.Equal(t, map[string]any{"deeper": "... (max depth)"}, got["nested"])
+	assert.Equal(t, "NaN", got["nan"])
+	assert.Equal(t, "-Inf", got["inf"])
+	assert.Equal(t, "(1+2i)", got["c"])
+	assert.Equal(t, "aGk=", got["bytes"])
+	assert.Equal(t, float64(math.MaxUint64), got["big"])
+	assert.Contains(t, got["ptr"].(string), "0x")
+
+	// Kinds encoding/json rejects are described instead of failing the dump.
+	out := DumpJSONStr(make(chan string, 3), namedFuncForTest, (*jsonAccount)(nil))
+	assert.JSONEq(t, `["chan string (len=0 cap=3)", "github.com/goforj/godump.namedFuncForTest", null]`, out)
+
+	// Values are not HTML-escaped.
+	assert.Equal(t, `"<b>&</b>"`, DumpJSONStr("<b>&</b>"))
+}
+
+func TestDumpJSONTyped(t *testing.T) {
+	type User struct {
+		Name  string
+		Tags  []string
+		Token string
+	}
+	d := NewDumper(WithJSONMode(JSONTyped), WithRedactFields("Token"), WithMaxItems(1))
+	out := d.DumpJSONStr(&User{Name: "Alice", Tags: []string{"a", "b"}, Token: "t"})
+
+	assert.Equal(t, `{
+  "type": "*godump.User",
+  "fields": {
+    "Name": {
+      "type": "string",
+      "value": "Alice"
+    },
+    "Tags": {
+      "type": "[]string",
+      "len": 2,
+      "cap": 2,
+      "items": [
+        {
+          "type": "string",
+          "value": "a"
+        }
+      ],
+      "truncated": true
+    },
+    "Token": {
+      "type": "string",
+      "redacted": true
+    }
+  }
+}`, out)
+}
+
+func TestDumpJSONTypedRefsAndMaps(t *testing.T) {
+	acct := &jsonAccount{ID: 1}
+	acct.Next = acct
+	shared := []int{1}
+	v := map[int]any{1: shared, 2: shared}
+	d := NewDumper(WithJSONMode(JSONTyped), WithOnlyFields("ID", "Next"))
+
+	assert.JSONEq(t, `{
+		"type": "*godump.jsonAccount",
+		"id": 1,
+		"fields": {
+			"ID": {"type": "int", "value": 1},
+			"Next": {"type": "*godump.jsonAccount", "ref": 1}
+		}
+	}`, d.DumpJSONStr(acct))
+
+	assert.JSONEq(t, `{
+		"type": "map[int]interface {}",
+		"len": 2,
+		"entries": [
+			{"key": {"type": "int", "value": 1}, "value": {"type": "[]int", "id": 1, "len": 1, "cap": 1, "items": [{"type": "int", "value": 1}]}},
+			{"key": {"type": "int", "value": 2}, "value": {"type": "[]int", "ref": 1}}
+		]
+	}`, d.DumpJSONStr(v))
+}
+
+func TestDumpJSONHooksErrorsAndStringers(t *testing.T) {
+	type Cents int64
+	type Pair struct{ A, B int }
+	d := NewDumper(
+		WithTypeFormatter(reflect.TypeOf(Cents(0)), func(v reflect.Value) string {
+			return fmt.Sprintf("$%d.%02d", v.Int()/100, v.Int()%100)
+		}),
+		WithTypeExpander(reflect.TypeOf(Pair{}), func(v reflect.Value) []Child {
+			return []Child{{Label: "sum", Value: v.Field(0).Int() + v.Field(1).Int()}}
+		}),
+	)
+	err := fmt.Errorf("load: %w", errors.New("missing"))
+
+	assert.JSONEq(t, `["$19.99", {"sum": 3}, "load: missing", "1s"]`, d.DumpJSONStr(Cents(1999), Pair{A: 1, B: 2}, err, jsonDuration(1)))
+
+	typed := NewDumper(WithJSONMode(JSONTyped)).DumpJSONStr(err)
+	assert.JSONEq(t, `{
+		"type": "*fmt.wrapError",
+		"value": "load: missing",
+		"unwrap": [{"type": "*errors.errorString", "value": "missing"}]
+	}`, typed)
+
+	// WithDisableStringer shows the underlying value instead.
+	assert.Equal(t, "1", NewDumper(WithDisableStringer(true)).DumpJSONStr(jsonDuration(1)))
+}
+
+type jsonBase struct {
+	ID      int `json:"id"`
+	Created time.Time
+	Hidden  string `json:"-"`
+}
+
+type jsonAudit struct {
+	By string `json:"by,omitempty"`
+	ID int
+}
+
+type jsonEvent struct {
+	jsonBase
+	*jsonAudit
+	Payload json.RawMessage
+	Level   jsonLevel
+	Tags    map[jsonLevel]int
+	Note    string `json:"note,omitempty"`
+	Count   int    `json:",omitempty"`
+	secret  string
+}
+
+type jsonLevel int
+
+func (l jsonLevel) MarshalText() ([]byte, error) { return []byte(fmt.Sprintf("L%d", int(l))), nil }
+
+func TestDumpJSONPlainFollowsEncodingJSON(t *testing.T) {
+	ev := jsonEvent{
+		jsonBase:  jsonBase{ID: 7, Created: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), Hidden: "h"},
+		jsonAudit: &jsonAudit{By: "ops", ID: 9},
+		Payload:   json.RawMessage(`{"raw": [1, 2]}`),
+		Level:     2,
+		Tags:      map[jsonLevel]int{1: 10},
+		secret:    "s",
+	}
+	want, err := json.Marshal(ev)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(want), DumpJSONStr(ev))
+	assert.JSONEq(t, `{
+		"id": 7,
+		"Created": "2024-05-01T12:00:00Z",
+		"by": "ops",
+		"ID": 9,
+		"Payload": {"raw": [1, 2]},
+		"Level": "L2",
+		"Tags": {"L1": 10}
+	}`, DumpJSONStr(ev))
+
+	// A nil embedded pointer promotes nothing.
+	ev.jsonAudit = nil
+	want, err = json.Marshal(&ev)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(want), DumpJSONStr(&ev))
+
+	// godump options still apply to the fields encoding/json writes.
+	assert.JSONEq(t, `{"id": 7, "Created": "2024-05-01T12:00:00Z", "Payload": {"raw": [1, 2]}, "Level": "<redacted>", "Tags": {"L1": 10}}`,
+		NewDumper(WithRedactFields("Level")).DumpJSONStr(ev))
+}
+
+func TestDumpJSONPlainSharedValues(t *testing.T) {
+	type Tree struct {
+		Left, Right *Tree
+		Name        string
+	}
+	// Every level refers to the one below twice; expanding each reference
+	// would double the document per level.
+	leaf := &Tree{Name: "leaf"}
+	root := leaf
+	for i := 0; i < 40; i++ {
+		root = &Tree{Left: root, Right: root, Name: "node"}
+	}
+	out := NewDumper(WithMaxDepth(100)).DumpJSONStr(root)
+	assert.True(t, len(out) < 20000)
+	assert.Equal(t, 40, strings.Count(out, `"$ref"`))
+
+	v := map[string]any{"a": leaf, "b": leaf}
+	assert.JSONEq(t, `{"a": {"$id": 1, "Left": null, "Right": null, "Name": "leaf"}, "b": {"$ref": 1}}`, DumpJSONStr(v))
+}
+
+type jsonDuration int
+
+func (d jsonDuration) String() string { return fmt.Sprintf("%ds", int(d)) }
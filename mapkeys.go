@@ -0,0 +1,305 @@
+package godump
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// MapKeyOrderSorted prints map entries in natural key order (the default).
+	MapKeyOrderSorted MapKeyOrder = iota
+	// MapKeyOrderHash prints map entries ordered by a stable hash of their keys.
+	MapKeyOrderHash
+	// MapKeyOrderCustom prints map entries using the comparator set by WithMapKeyComparator.
+	MapKeyOrderCustom
+)
+
+// MapKeyOrder controls the order in which map entries are printed.
+type MapKeyOrder int
+
+// MapKeyComparator compares two map keys and returns a negative number when a
+// sorts before b, a positive number when a sorts after b, and zero otherwise.
+type MapKeyComparator func(a, b reflect.Value) int
+
+// maxKeyCompareDepth bounds how far pointer keys are followed while comparing.
+const maxKeyCompareDepth = 8
+
+// WithMapKeyOrder sets the order in which map entries are printed.
+// @group Options
+//
+// Example: order map entries by key hash
+//
+//	// Default: MapKeyOrderSorted
+//	v := map[string]int{"b": 1, "c": 2}
+//	d := godump.NewDumper(godump.WithMapKeyOrder(godump.MapKeyOrderHash))
+//	d.Dump(v)
+//	// #map[string]int {
+//	//   c => 2 #int
+//	//   b => 1 #int
+//	// }
+func WithMapKeyOrder(order MapKeyOrder) Option {
+	return func(d *Dumper) *Dumper {
+		d.mapKeyOrder = order
+		return d
+	}
+}
+
+// WithMapKeyComparator orders map entries using a caller-supplied comparator.
+// It implies MapKeyOrderCustom; a nil comparator falls back to sorted order.
+// @group Options
+//
+// Example: order map entries in reverse
+//
+//	// Default: none
+//	v := map[int]string{1: "a", 2: "b"}
+//	d := godump.NewDumper(godump.WithMapKeyComparator(func(a, b reflect.Value) int {
+//		return int(b.Int() - a.Int())
+//	}))
+//	d.Dump(v)
+//	// #map[int]string {
+//	//   2 => "b" #string
+//	//   1 => "a" #string
+//	// }
+func WithMapKeyComparator(cmp MapKeyComparator) Option {
+	return func(d *Dumper) *Dumper {
+		d.mapKeyOrder = MapKeyOrderCustom
+		d.mapKeyComparator = cmp
+		return d
+	}
+}
+
+// sortedMapKeys returns the keys of map v in the configured print order.
+func (d *Dumper) sortedMapKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+
+	switch {
+	case d.mapKeyOrder == MapKeyOrderCustom && d.mapKeyComparator != nil:
+		sort.SliceStable(keys, func(i, j int) bool {
+			return d.mapKeyComparator(keys[i], keys[j]) < 0
+		})
+	case d.mapKeyOrder == MapKeyOrderHash:
+		hashes := make([]uint64, len(keys))
+		for i, key := range keys {
+			hashes[i] = hashMapKey(key)
+		}
+		sort.Sort(hashedKeys{keys: keys, hashes: hashes})
+	default:
+		sort.SliceStable(keys, func(i, j int) bool {
+			return compareKeys(keys[i], keys[j], 0) < 0
+		})
+	}
+
+	return keys
+}
+
+// hashedKeys sorts map keys by precomputed hash, breaking ties by natural order.
+type hashedKeys struct {
+	keys   []reflect.Value
+	hashes []uint64
+}
+
+func (h hashedKeys) Len() int { return len(h.keys) }
+
+func (h hashedKeys) Less(i, j int) bool {
+	if h.hashes[i] != h.hashes[j] {
+		return h.hashes[i] < h.hashes[j]
+	}
+	return compareKeys(h.keys[i], h.keys[j], 0) < 0
+}
+
+func (h hashedKeys) Swap(i, j int) {
+	h.keys[i], h.keys[j] = h.keys[j], h.keys[i]
+	h.hashes[i], h.hashes[j] = h.hashes[j], h.hashes[i]
+}
+
+// hashMapKey returns a stable FNV-1a hash of a key's type and value.
+// Pointers and interfaces are hashed by what they hold, so the hash does not
+// depend on where the key was allocated.
+func hashMapKey(v reflect.Value) uint64 {
+	h := fnv.New64a()
+	hashKeyValue(h, v, 0)
+	return h.Sum64()
+}
+
+// hashKeyValue writes the structure of v to h.
+func hashKeyValue(h hash.Hash64, v reflect.Value, depth int) {
+	var buf [8]byte
+	writeUint := func(u uint64) {
+		binary.LittleEndian.PutUint64(buf[:], u)
+		h.Write(buf[:])
+	}
+
+	if !v.IsValid() {
+		writeUint(0)
+		return
+	}
+	io.WriteString(h, v.Type().String())
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeUint(math.Float64bits(real(v.Complex())))
+		writeUint(math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		writeUint(uint64(v.Len()))
+		io.WriteString(h, v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			writeUint(0)
+			return
+		}
+		writeUint(1)
+		if depth < maxKeyCompareDepth {
+			hashKeyValue(h, v.Elem(), depth+1)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			hashKeyValue(h, v.Field(i), depth)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashKeyValue(h, v.Index(i), depth)
+		}
+	}
+	// Channels and unsafe pointers are only their address, which changes
+	// from run to run, so they are hashed by type alone.
+}
+
+// compareKeys orders two map keys: numbers, strings and bools naturally, NaN
+// before other floats, nil before non-nil, structs and arrays element by
+// element, pointers by their pointee and interfaces by dynamic type then value.
+func compareKeys(a, b reflect.Value, depth int) int {
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return 0
+	case !a.IsValid():
+		return -1
+	case !b.IsValid():
+		return 1
+	}
+
+	if a.Kind() != b.Kind() {
+		return strings.Compare(a.Type().String(), b.Type().String())
+	}
+
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(a.Uint(), b.Uint())
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Float32, reflect.Float64:
+		return compareFloats(a.Float(), b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		ac, bc := a.Complex(), b.Complex()
+		if c := compareFloats(real(ac), real(bc)); c != 0 {
+			return c
+		}
+		return compareFloats(imag(ac), imag(bc))
+	case reflect.Bool:
+		switch {
+		case a.Bool() == b.Bool():
+			return 0
+		case a.Bool():
+			return 1
+		default:
+			return -1
+		}
+	case reflect.Ptr:
+		if c := compareNil(a, b); c != 0 || a.IsNil() {
+			return c
+		}
+		if depth < maxKeyCompareDepth {
+			if c := compareKeys(a.Elem(), b.Elem(), depth+1); c != 0 {
+				return c
+			}
+		}
+		return compareOrdered(a.Pointer(), b.Pointer())
+	case reflect.Chan, reflect.UnsafePointer:
+		return compareOrdered(a.Pointer(), b.Pointer())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if c := compareKeys(a.Field(i), b.Field(i), depth); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if c := compareKeys(a.Index(i), b.Index(i), depth); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Interface:
+		if c := compareNil(a, b); c != 0 || a.IsNil() {
+			return c
+		}
+		ae, be := a.Elem(), b.Elem()
+		if c := strings.Compare(ae.Type().String(), be.Type().String()); c != 0 {
+			return c
+		}
+		return compareKeys(ae, be, depth)
+	default:
+		return 0
+	}
+}
+
+// compareNil orders nil values before non-nil ones.
+func compareNil(a, b reflect.Value) int {
+	switch {
+	case a.IsNil() && b.IsNil():
+		return 0
+	case a.IsNil():
+		return -1
+	case b.IsNil():
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareFloats orders floats naturally, with NaN sorting before everything else.
+func compareFloats(a, b float64) int {
+	aNaN, bNaN := math.IsNaN(a), math.IsNaN(b)
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return -1
+	case bNaN:
+		return 1
+	default:
+		return compareOrdered(a, b)
+	}
+}
+
+// compareOrdered compares two ordered values.
+func compareOrdered[T int64 | uint64 | uintptr | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
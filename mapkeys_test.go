@@ -0,0 +1,182 @@
+package godump
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+func TestMapKeysSortedByDefault(t *testing.T) {
+	m := map[string]int{"c": 3, "a": 1, "b": 2}
+	out := newDumperT(t, WithoutHeader()).DumpStr(m)
+
+	a := strings.Index(out, "a =>")
+	b := strings.Index(out, "b =>")
+	c := strings.Index(out, "c =>")
+	assert.True(t, a < b && b < c, out)
+}
+
+func TestMapKeysDeterministicOutput(t *testing.T) {
+	m := map[int]string{}
+	for i := 0; i < 50; i++ {
+		m[i*7%50] = "v"
+	}
+
+	first := newDumperT(t, WithoutHeader()).DumpStr(m)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, newDumperT(t, WithoutHeader()).DumpStr(m))
+	}
+}
+
+func TestMapKeysTruncationKeepsSmallestKeys(t *testing.T) {
+	m := map[int]int{}
+	for i := 0; i < 10; i++ {
+		m[i] = i
+	}
+
+	out := newDumperT(t, WithoutHeader(), WithMaxItems(3)).DumpStr(m)
+	assert.Contains(t, out, "0 => 0")
+	assert.Contains(t, out, "2 => 2")
+	assert.NotContains(t, out, "3 => 3")
+	assert.Contains(t, out, "... (truncated)")
+}
+
+func TestMapKeyOrderHashIsStable(t *testing.T) {
+	m := map[string]int{"alpha": 1, "beta": 2, "gamma": 3, "delta": 4}
+	d := newDumperT(t, WithoutHeader(), WithMapKeyOrder(MapKeyOrderHash))
+
+	first := d.DumpStr(m)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, d.DumpStr(m))
+	}
+
+	keys := d.sortedMapKeys(reflect.ValueOf(m))
+	for i := 1; i < len(keys); i++ {
+		assert.True(t, hashMapKey(keys[i-1]) <= hashMapKey(keys[i]))
+	}
+}
+
+func TestMapKeyOrderHashIgnoresAddresses(t *testing.T) {
+	type key struct {
+		Name *string
+		N    int
+	}
+	build := func() (map[*int]string, map[key]int) {
+		ptrs := map[*int]string{}
+		structs := map[key]int{}
+		for i := 0; i < 20; i++ {
+			n := i
+			name := strconv.Itoa(i)
+			ptrs[&n] = name
+			structs[key{Name: &name, N: i}] = i
+		}
+		return ptrs, structs
+	}
+
+	d := newDumperT(t, WithoutHeader(), WithMapKeyOrder(MapKeyOrderHash))
+	// order returns the values of m in the order their keys are printed.
+	order := func(m any) []string {
+		v := reflect.ValueOf(m)
+		var out []string
+		for _, k := range d.sortedMapKeys(v) {
+			out = append(out, fmt.Sprint(v.MapIndex(k)))
+		}
+		return out
+	}
+	ptrsA, structsA := build()
+	ptrsB, structsB := build()
+	assert.Equal(t, order(ptrsA), order(ptrsB))
+	assert.Equal(t, order(structsA), order(structsB))
+
+	one, two := 1, 1
+	assert.Equal(t, hashMapKey(reflect.ValueOf(&one)), hashMapKey(reflect.ValueOf(&two)))
+}
+
+func TestMapKeyComparator(t *testing.T) {
+	m := map[int]string{1: "a", 2: "b", 3: "c"}
+	d := newDumperT(t, WithoutHeader(), WithMapKeyComparator(func(a, b reflect.Value) int {
+		return int(b.Int() - a.Int())
+	}))
+	out := d.DumpStr(m)
+
+	assert.True(t, strings.Index(out, "3 =>") < strings.Index(out, "1 =>"), out)
+
+	d = newDumperT(t, WithoutHeader(), WithMapKeyComparator(nil))
+	out = d.DumpStr(m)
+	assert.True(t, strings.Index(out, "1 =>") < strings.Index(out, "3 =>"), out)
+}
+
+func TestCompareKeys(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	one, two := 1, 2
+	nan := math.NaN()
+
+	tests := []struct {
+		name string
+		a, b any
+		want int
+	}{
+		{name: "ints", a: 1, b: 2, want: -1},
+		{name: "uints", a: uint(5), b: uint(3), want: 1},
+		{name: "strings", a: "a", b: "a", want: 0},
+		{name: "floats", a: 1.5, b: 0.5, want: 1},
+		{name: "nan first", a: nan, b: -1.0, want: -1},
+		{name: "nan equal", a: nan, b: nan, want: 0},
+		{name: "complex", a: complex(1, 2), b: complex(1, 3), want: -1},
+		{name: "bools", a: false, b: true, want: -1},
+		{name: "structs", a: point{1, 2}, b: point{1, 1}, want: 1},
+		{name: "arrays", a: [2]int{1, 2}, b: [2]int{1, 3}, want: -1},
+		{name: "pointers by pointee", a: &two, b: &one, want: 1},
+		{name: "nil pointer first", a: (*int)(nil), b: &one, want: -1},
+		{name: "mixed kinds by type", a: "x", b: 1, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compareKeys(reflect.ValueOf(tt.a), reflect.ValueOf(tt.b), 0)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, 0, compareKeys(reflect.Value{}, reflect.Value{}, 0))
+	assert.Equal(t, -1, compareKeys(reflect.Value{}, reflect.ValueOf(1), 0))
+	assert.Equal(t, 1, compareKeys(reflect.ValueOf(1), reflect.Value{}, 0))
+}
+
+func TestMapKeysInterfaceKeys(t *testing.T) {
+	m := map[any]int{"b": 1, 2: 2, "a": 3, 1: 4, nil: 5}
+	keys := newDumperT(t).sortedMapKeys(reflect.ValueOf(m))
+
+	var got []any
+	for _, k := range keys {
+		got = append(got, k.Interface())
+	}
+	assert.Equal(t, []any{nil, 1, 2, "a", "b"}, got)
+}
+
+func TestDiffMapsAlignEntries(t *testing.T) {
+	left := map[string]int{}
+	right := map[string]int{}
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		left[k] = 1
+		right[k] = 1
+	}
+	right["d"] = 2
+
+	out := newDumperT(t, WithoutHeader(), WithoutColor()).DiffStr(left, right)
+	var changed []string
+	for _, line := range splitLines(out) {
+		if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+") {
+			changed = append(changed, line)
+		}
+	}
+
+	assert.Equal(t, []string{"-    d => 1 #int", "+    d => 2 #int"}, changed)
+}
@@ -0,0 +1,608 @@
+package godump
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+)
+
+const (
+	// NodeInvalid is an invalid value, such as the zero reflect.Value.
+	NodeInvalid NodeKind = iota
+	// NodeNil is a nil pointer, map, slice, interface, channel or func.
+	NodeNil
+	// NodeRef refers back to the node whose ID is Ref.
+	NodeRef
+	// NodeScalar is a bool or a number; Value holds its text and Raw its value.
+	NodeScalar
+	// NodeString is a string; Value holds it, cut to WithMaxStringLen.
+	NodeString
+	// NodeText is text produced by a Stringer, a TypeFormatter or a stack
+	// trace. Stack frames have no Type, and closure captures no Value.
+	NodeText
+	// NodeError is an error. Value holds its message, and errors that wrap
+	// others or carry a stack trace have "Error", "Unwrap" and "Stack" children.
+	NodeError
+	// NodeStruct is a struct; its children are the printed fields.
+	NodeStruct
+	// NodeMap is a map; its children are the entries, each with a Key.
+	NodeMap
+	// NodeList is a slice or an array; its children are the elements.
+	NodeList
+	// NodeBytes is a byte slice, whose contents are in Bytes.
+	NodeBytes
+	// NodeChan is a channel; its children are the buffered elements shown
+	// with WithChannelBuffers.
+	NodeChan
+	// NodeFunc is a function; Value holds its name and its children are the
+	// captures shown with WithClosureCaptures.
+	NodeFunc
+	// NodeUnsafePointer is an unsafe.Pointer; Value holds the address.
+	NodeUnsafePointer
+	// NodeInterface is a non-nil interface whose dynamic value is its only child.
+	NodeInterface
+	// NodeExpanded is a value rendered through a TypeExpander; its children
+	// are the expander's output.
+	NodeExpanded
+)
+
+// NodeKind says how a [Node] is shown.
+type NodeKind int
+
+var nodeKindNames = [...]string{
+	NodeInvalid:       "invalid",
+	NodeNil:           "nil",
+	NodeRef:           "ref",
+	NodeScalar:        "scalar",
+	NodeString:        "string",
+	NodeText:          "text",
+	NodeError:         "error",
+	NodeStruct:        "struct",
+	NodeMap:           "map",
+	NodeList:          "list",
+	NodeBytes:         "bytes",
+	NodeChan:          "chan",
+	NodeFunc:          "func",
+	NodeUnsafePointer: "unsafe pointer",
+	NodeInterface:     "interface",
+	NodeExpanded:      "expanded",
+}
+
+// String returns the name of the kind.
+func (k NodeKind) String() string {
+	if k >= 0 && int(k) < len(nodeKindNames) {
+		return nodeKindNames[k]
+	}
+	return "NodeKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Node is a value as the dumper shows it, with every option of the [Dumper]
+// applied: field filters and tags, redaction, type hooks, Stringers, number
+// formats and the depth, item and string limits. The text, HTML and JSON
+// output are rendered from this tree, and it can be walked to write other
+// formats.
+//
+// Text in a node is raw; renderers escape and sanitize it for their output.
+type Node struct {
+	// Kind says how the value is shown.
+	Kind NodeKind
+	// Type is the rendered type name, including pointer stars.
+	Type string
+	// Name is the node's label in its parent: a struct field name, map key,
+	// element index or expander label. It is empty for top-level values.
+	Name string
+	// Key is the key of a map entry.
+	Key *Node
+	// Unexported marks struct fields that are not exported.
+	Unexported bool
+	// Tag is the struct tag of a struct field.
+	Tag reflect.StructTag
+	// Value is the text of scalars, strings, Stringers, error messages,
+	// function names and unsafe pointers, and a channel's address.
+	Value string
+	// Raw is the value of a scalar or string as a bool, int64, uint64,
+	// float32, float64, complex64, complex128 or string. Strings are not cut.
+	Raw any
+	// Bytes holds the contents of a byte slice.
+	Bytes []byte
+	// Len is the length of slices, arrays, maps, byte slices and channels.
+	Len int
+	// Cap is the capacity of slices, byte slices and channels.
+	Cap int
+	// Note is the kind of a function ("closure" or "method value") or the
+	// direction of a channel ("both", "send" or "recv").
+	Note string
+	// Location is where a function is defined, as "file:line".
+	Location string
+	// Closed reports that a channel whose buffer was read is closed.
+	Closed bool
+	// ID is the anchor of a value that is reached again later; 0 if none.
+	ID int
+	// Ref is the ID of the node a NodeRef refers back to.
+	Ref int
+	// Redacted marks a field whose value was hidden. Kind and Type describe
+	// the hidden value.
+	Redacted bool
+	// Truncated marks a node whose children or text were cut by
+	// WithMaxItems or WithMaxStringLen.
+	Truncated bool
+	// MaxDepth marks a node whose children were not shown because of
+	// WithMaxDepth. Kind and Type describe the value.
+	MaxDepth bool
+	// BudgetExceeded marks the top-level node during which the limits of
+	// WithMaxNodes, WithMaxOutputBytes, WithTimeout or DumpContext ran out.
+	// Its containers cut short are Truncated, and later values may be too.
+	BudgetExceeded bool
+	// Addrs are the addresses shown by WithAddresses: each pointer followed,
+	// then the map, slice or boxed value.
+	Addrs []uintptr
+	// Alias is the ID of an earlier value whose memory this one overlaps.
+	Alias int
+	// Children are the fields, entries, elements, captures, expander output
+	// or error parts of the value.
+	Children []*Node
+
+	// elemType is a channel's element type.
+	elemType string
+	// slice marks a NodeList holding a slice rather than an array.
+	slice bool
+	// noBuffer marks a channel whose buffer could not be read.
+	noBuffer bool
+	// noCaptures marks a closure whose captures could not be located.
+	noCaptures bool
+	// next inspects the next child of a node whose children are inspected
+	// while it is printed, and returns nil after the last one.
+	next func() *Node
+	// fieldLabels are the labels of the fields a struct node may print,
+	// which align its fields before they are inspected.
+	fieldLabels []string
+	// rawJSON is the encoding/json output of a value written by its own
+	// marshaler in plain JSON.
+	rawJSON json.RawMessage
+}
+
+// Inspect returns the node tree the default dumper renders for v.
+// @group Inspect
+//
+// Example: walk the node tree
+//
+//	type User struct {
+//		Name  string
+//		Email string
+//	}
+//	n := godump.Inspect(User{Name: "Alice", Email: "a@example.com"})
+//	for _, f := range n.Children {
+//		fmt.Println(f.Name, f.Kind, f.Value)
+//	}
+//	// Name string Alice
+//	// Email string a@example.com
+func Inspect(v any) *Node {
+	return defaultDumper.Inspect(v)
+}
+
+// Inspect returns the node tree the dumper renders for v, with all of its
+// options applied.
+// @group Inspect
+//
+// Example: inspect with options
+//
+//	type User struct {
+//		Name     string
+//		Password string
+//	}
+//	d := godump.NewDumper(godump.WithRedactFields("Password"))
+//	n := d.Inspect(&User{Name: "Alice", Password: "hunter2"})
+//	fmt.Println(n.Type, n.Children[1].Name, n.Children[1].Redacted)
+//	// *main.User Password true
+func (d *Dumper) Inspect(v any) *Node {
+	return d.inspectValues([]any{v})[0]
+}
+
+// inspectValues builds the trees for values dumped together, which share
+// reference ids, within the dumper's output budget. Nodes, such as those
+// returned by Parse, are used as is.
+func (d *Dumper) inspectValues(vs []any) []*Node {
+	state, values := d.newInspection(vs)
+	nodes := make([]*Node, len(values))
+	for i := range values {
+		cut := state.budget.cut()
+		nodes[i] = d.inspectAt(vs, values, i, state)
+		if !cut && state.budget.cut() {
+			nodes[i].BudgetExceeded = true
+		}
+	}
+	return nodes
+}
+
+// newInspection prepares the state shared by values dumped together: their
+// references are counted first, so that only values reached more than once
+// get an anchor.
+func (d *Dumper) newInspection(vs []any) (*dumpState, []reflect.Value) {
+	state := newDumpState()
+	state.budget = d.newBudget()
+	values := make([]reflect.Value, len(vs))
+	counts := map[refKey]int{}
+	for i, v := range vs {
+		if n, ok := v.(*Node); ok && n != nil {
+			continue
+		}
+		values[i] = makeAddressable(reflect.ValueOf(v))
+		d.countRefs(values[i], 0, state, counts)
+	}
+	linkAliases(state)
+	return state, values
+}
+
+// inspectAt builds the node for the i-th of the values prepared by
+// newInspection. Nodes, such as those returned by Parse, are used as is.
+func (d *Dumper) inspectAt(vs []any, values []reflect.Value, i int, state *dumpState) *Node {
+	if n, ok := vs[i].(*Node); ok && n != nil {
+		return n
+	}
+	return d.inspectValue(values[i], 0, state)
+}
+
+// inspectValue builds the node for v at the given depth.
+func (d *Dumper) inspectValue(v reflect.Value, depth int, state *dumpState) *Node {
+	state.budget.take()
+	if !v.IsValid() {
+		return &Node{Kind: NodeInvalid}
+	}
+
+	typeStr := d.typeString(v.Type())
+	if isNil(v) {
+		return &Node{Kind: NodeNil, Type: typeStr}
+	}
+
+	if shouldTruncateAtDepth(v, depth, d.maxDepth) {
+		return &Node{Kind: nodeKindOf(baseValue(v).Type()), Type: typeStr, MaxDepth: true}
+	}
+
+	if n := d.inspectTypeHook(v, depth, state); n != nil {
+		return n
+	}
+
+	if n := d.inspectMarshaler(v, typeStr); n != nil {
+		return n
+	}
+
+	if n := d.inspectError(v, depth, state); n != nil {
+		return n
+	}
+
+	if s, nilPtr, ok := d.stringerText(v); ok {
+		if nilPtr {
+			return &Node{Kind: NodeNil, Type: v.Type().String()}
+		}
+		return &Node{Kind: NodeText, Type: typeStr, Value: s}
+	}
+
+	if v.Kind() == reflect.Chan {
+		return d.inspectChan(v, depth, state)
+	}
+
+	n := &Node{Type: typeStr}
+	key, hasKey := refKeyOf(v)
+	if hasKey {
+		id, seen := trackRef(key, state)
+		if seen {
+			return &Node{Kind: NodeRef, Type: typeStr, Ref: id}
+		}
+		n.ID = id
+		n.Alias = state.refs[state.aliases[key]]
+	}
+	d.inspectAddresses(n, v)
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			n.Kind = NodeNil
+			return n
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		n.Kind = NodeInterface
+		n.Children = []*Node{d.inspectValue(v.Elem(), depth, state)}
+	case reflect.Struct:
+		d.inspectStruct(n, v, depth, state)
+	case reflect.Map:
+		d.inspectMap(n, v, depth, state)
+	case reflect.Slice, reflect.Array:
+		d.inspectList(n, v, depth, state)
+	case reflect.String:
+		n.Kind = NodeString
+		n.Value, n.Truncated = truncateRunes(v.String(), d.maxStringLen)
+		n.Raw = v.String()
+	case reflect.UnsafePointer:
+		n.Kind = NodeUnsafePointer
+		n.Value = fmt.Sprintf("%#x", v.Pointer())
+	case reflect.Func:
+		d.inspectFunc(n, v, depth, state)
+	default:
+		n.Kind = NodeScalar
+		n.Value, n.Raw = d.scalarText(v)
+	}
+	return n
+}
+
+// inspectStruct adds the printed fields of struct v to n.
+func (d *Dumper) inspectStruct(n *Node, v reflect.Value, depth int, state *dumpState) {
+	n.Kind = NodeStruct
+	if d.jsonPlain {
+		d.inspectJSONFields(n, v, depth, state)
+		return
+	}
+	p := d.plan(v.Type())
+	n.fieldLabels = p.labels
+	i := 0
+	d.setChildren(n, state, func() *Node {
+		if i == len(p.fields) {
+			return nil
+		}
+		if !state.budget.allows() {
+			n.Truncated = true
+			return nil
+		}
+		i++
+		return d.inspectField(v, p.fields[i-1], depth, state)
+	})
+}
+
+// setChildren fills the children of n from next. When the dump is streamed,
+// it leaves them to be inspected as n is printed.
+func (d *Dumper) setChildren(n *Node, state *dumpState, next func() *Node) {
+	if state.stream {
+		n.next = next
+		return
+	}
+	for child := next(); child != nil; child = next() {
+		n.Children = append(n.Children, child)
+	}
+}
+
+// pull returns the next pending child of n, or nil when there is none.
+func (n *Node) pull() *Node {
+	if n.next == nil {
+		return nil
+	}
+	child := n.next()
+	if child == nil {
+		n.next = nil
+	}
+	return child
+}
+
+// eachChild calls fn with each child of n in order, inspecting pending ones
+// as it goes. Pending children are not kept, so a streamed dump only holds
+// the nodes on the path to the value being printed.
+func (n *Node) eachChild(fn func(*Node)) {
+	for _, child := range n.Children {
+		fn(child)
+	}
+	if n.next == nil {
+		return
+	}
+	n.Children = nil
+	for child := n.pull(); child != nil; child = n.pull() {
+		fn(child)
+	}
+}
+
+// prefetch inspects the pending descendants of n, counting every node
+// against limit. It reports false, leaving the rest pending, once more than
+// limit nodes are found.
+func prefetch(n *Node, limit *int) bool {
+	for i := 0; ; i++ {
+		if i == len(n.Children) {
+			child := n.pull()
+			if child == nil {
+				return true
+			}
+			n.Children = append(n.Children, child)
+		}
+		*limit--
+		if *limit < 0 || !prefetch(n.Children[i], limit) {
+			return false
+		}
+	}
+}
+
+// inspectField builds the node for field f of struct v.
+func (d *Dumper) inspectField(v reflect.Value, f fieldPlan, depth int, state *dumpState) *Node {
+	fieldVal := v.Field(f.index)
+	if !f.exported {
+		fieldVal = forceExported(fieldVal)
+	}
+
+	var child *Node
+	if f.redact {
+		child = &Node{Kind: nodeKindOf(fieldVal.Type()), Type: d.typeString(fieldVal.Type()), Redacted: true}
+	} else {
+		child = d.fieldDumper(f.tag, depth+1).inspectValue(fieldVal, depth+1, state)
+	}
+	child.Name = f.label
+	child.Unexported = !f.exported
+	child.Tag = v.Type().Field(f.index).Tag
+	return child
+}
+
+// inspectMap adds the entries of map v to n in the configured key order.
+func (d *Dumper) inspectMap(n *Node, v reflect.Value, depth int, state *dumpState) {
+	n.Kind = NodeMap
+	n.Len = v.Len()
+
+	// Keys are built apart so they do not take part in reference tracking.
+	keyState := newDumpState()
+	keys := d.sortedMapKeys(v)
+	i := 0
+	d.setChildren(n, state, func() *Node {
+		if i == len(keys) {
+			return nil
+		}
+		if i >= d.maxItems || !state.budget.allows() {
+			n.Truncated = true
+			return nil
+		}
+		key := keys[i]
+		i++
+		child := d.inspectValue(v.MapIndex(key), depth+1, state)
+		// fmt formats a reflect.Value as the value it holds, without the
+		// Interface call that panics on maps reached through unexported
+		// fields.
+		child.Name = fmt.Sprintf("%v", key)
+		if name, ok := d.jsonMapKey(key); ok {
+			child.Name = name
+		}
+		child.Key = d.inspectValue(key, depth+1, keyState)
+		return child
+	})
+}
+
+// inspectList adds the elements of slice or array v to n. Byte slices keep
+// their contents instead.
+func (d *Dumper) inspectList(n *Node, v reflect.Value, depth int, state *dumpState) {
+	n.Len = v.Len()
+	if v.Kind() == reflect.Slice {
+		n.Cap = v.Cap()
+	}
+	if v.Type().Elem().Kind() == reflect.Uint8 && v.CanConvert(reflect.TypeOf([]byte{})) {
+		n.Kind = NodeBytes
+		n.Bytes = v.Convert(reflect.TypeOf([]byte{})).Bytes()
+		return
+	}
+
+	n.Kind = NodeList
+	n.slice = v.Kind() == reflect.Slice
+	i := 0
+	d.setChildren(n, state, func() *Node {
+		if i == v.Len() {
+			return nil
+		}
+		if i >= d.maxItems || !state.budget.allows() {
+			n.Truncated = true
+			return nil
+		}
+		child := d.inspectValue(v.Index(i), depth+1, state)
+		child.Name = strconv.Itoa(i)
+		i++
+		return child
+	})
+}
+
+// inspectChildren adds labeled children, such as expander output, to n.
+func (d *Dumper) inspectChildren(n *Node, children []Child, depth int, state *dumpState) {
+	if len(children) > d.maxItems {
+		n.Truncated = true
+		children = children[:d.maxItems]
+	}
+	for _, c := range children {
+		if !state.budget.allows() {
+			n.Truncated = true
+			break
+		}
+		child := d.inspectValue(makeAddressable(reflect.ValueOf(c.Value)), depth+1, state)
+		child.Name = c.Label
+		n.Children = append(n.Children, child)
+	}
+}
+
+// inspectFunc fills n with the name, kind and definition site of function v,
+// and its captures when enabled.
+func (d *Dumper) inspectFunc(n *Node, v reflect.Value, depth int, state *dumpState) {
+	n.Kind = NodeFunc
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return
+	}
+
+	n.Value, n.Note = funcKind(fn.Name())
+	// Wrappers generated by the compiler, such as method values, have no
+	// meaningful source location.
+	if file, line := fn.FileLine(fn.Entry()); file != "" && file != "<autogenerated>" {
+		n.Location = fmt.Sprintf("%s:%d", relativePath(file), line)
+	}
+	if !d.showClosureCaptures || n.Note != "closure" {
+		return
+	}
+
+	captures, err := closureCaptures(fn)
+	switch {
+	case err != nil:
+		n.noCaptures = true
+	case len(captures) == 0:
+	case depth >= d.maxDepth:
+		n.MaxDepth = true
+	default:
+		for _, c := range captures {
+			n.Children = append(n.Children, &Node{Kind: NodeText, Name: c.name, Type: c.typ})
+		}
+	}
+}
+
+// scalarText returns the text and raw value of a bool or number, formatted
+// with the configured NumberFormat.
+func (d *Dumper) scalarText(v reflect.Value) (string, any) {
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return d.formatInt(v.Int()), v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return d.formatUint(v.Uint()), v.Uint()
+	case reflect.Float32:
+		return d.formatFloat(v.Float(), 32), float32(v.Float())
+	case reflect.Float64:
+		return d.formatFloat(v.Float(), 64), v.Float()
+	case reflect.Complex64:
+		return d.formatComplex(v.Complex(), 32), complex64(v.Complex())
+	case reflect.Complex128:
+		return d.formatComplex(v.Complex(), 64), v.Complex()
+	default:
+		return v.Type().String(), nil
+	}
+}
+
+// baseValue follows non-nil pointers and interfaces from v.
+func baseValue(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// nodeKindOf returns the kind of node values of type t are shown as, ignoring
+// pointers.
+func nodeKindOf(t reflect.Type) NodeKind {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return NodeString
+	case reflect.Struct:
+		return NodeStruct
+	case reflect.Map:
+		return NodeMap
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return NodeBytes
+		}
+		return NodeList
+	case reflect.Array:
+		return NodeList
+	case reflect.Chan:
+		return NodeChan
+	case reflect.Func:
+		return NodeFunc
+	case reflect.UnsafePointer:
+		return NodeUnsafePointer
+	case reflect.Interface:
+		return NodeInterface
+	default:
+		return NodeScalar
+	}
+}
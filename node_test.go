@@ -0,0 +1,170 @@
+package godump
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+type inspectUser struct {
+	Name     string `json:"name"`
+	Password string
+	Tags     []string
+	Meta     map[string]int
+	score    int `godump:"hex"`
+	Friend   *inspectUser
+}
+
+func TestInspectAppliesOptions(t *testing.T) {
+	u := &inspectUser{Name: "Alice", Password: "hunter2", Tags: []string{"a", "b", "c"}, Meta: map[string]int{"x": 1}, score: 255}
+	d := NewDumper(WithRedactFields("Password"), WithMaxItems(2), WithMaxStringLen(3), WithMaxDepth(1))
+	n := d.Inspect(u)
+
+	assert.Equal(t, NodeStruct, n.Kind)
+	assert.Equal(t, "*godump.inspectUser", n.Type)
+	assert.Equal(t, 6, len(n.Children))
+
+	name := n.Children[0]
+	assert.Equal(t, "Name", name.Name)
+	assert.Equal(t, NodeString, name.Kind)
+	assert.Equal(t, "Ali", name.Value)
+	assert.Equal(t, "Alice", name.Raw)
+	assert.True(t, name.Truncated)
+	assert.Equal(t, "name", name.Tag.Get("json"))
+
+	pw := n.Children[1]
+	assert.True(t, pw.Redacted)
+	assert.Equal(t, NodeString, pw.Kind)
+	assert.Equal(t, "", pw.Value)
+
+	tags := n.Children[2]
+	assert.Equal(t, NodeList, tags.Kind)
+	assert.True(t, tags.MaxDepth)
+	assert.Equal(t, 0, len(tags.Children))
+
+	score := n.Children[4]
+	assert.True(t, score.Unexported)
+	assert.Equal(t, "0xff", score.Value)
+	assert.Equal(t, int64(255), score.Raw)
+
+	assert.Equal(t, NodeNil, n.Children[5].Kind)
+
+	n = NewDumper(WithMaxItems(2)).Inspect(u.Tags)
+	assert.Equal(t, 3, n.Len)
+	assert.Equal(t, 3, n.Cap)
+	assert.True(t, n.Truncated)
+	assert.Equal(t, []string{"0", "1"}, []string{n.Children[0].Name, n.Children[1].Name})
+}
+
+func TestInspectRefsKeysAndKinds(t *testing.T) {
+	u := &inspectUser{Name: "Bob"}
+	u.Friend = u
+	n := Inspect(u)
+	assert.Equal(t, 1, n.ID)
+	friend := n.Children[5]
+	assert.Equal(t, NodeRef, friend.Kind)
+	assert.Equal(t, 1, friend.Ref)
+
+	n = Inspect(map[int]bool{2: false, 1: true})
+	assert.Equal(t, NodeMap, n.Kind)
+	assert.Equal(t, "1", n.Children[0].Name)
+	assert.Equal(t, NodeScalar, n.Children[0].Key.Kind)
+	assert.Equal(t, int64(1), n.Children[0].Key.Raw)
+	assert.Equal(t, true, n.Children[0].Raw)
+
+	n = Inspect([]any{[]byte("hi"), make(chan int, 2), namedFuncForTest, 1 + 2i})
+	assert.Equal(t, NodeInterface, n.Children[0].Kind)
+	kinds := make([]NodeKind, len(n.Children))
+	for i, c := range n.Children {
+		kinds[i] = c.Children[0].Kind
+	}
+	assert.Equal(t, []NodeKind{NodeBytes, NodeChan, NodeFunc, NodeScalar}, kinds)
+	assert.Equal(t, []byte("hi"), n.Children[0].Children[0].Bytes)
+	assert.Equal(t, "both", n.Children[1].Children[0].Note)
+	assert.Equal(t, "github.com/goforj/godump.namedFuncForTest", n.Children[2].Children[0].Value)
+	assert.Equal(t, "bytes", NodeBytes.String())
+	assert.Equal(t, "NodeKind(99)", NodeKind(99).String())
+}
+
+func TestInspectErrorsAndHooks(t *testing.T) {
+	err := fmt.Errorf("load: %w", errors.New("missing"))
+	n := Inspect(err)
+	assert.Equal(t, NodeError, n.Kind)
+	assert.Equal(t, "load: missing", n.Value)
+	assert.Equal(t, []string{"Error", "Unwrap"}, []string{n.Children[0].Name, n.Children[1].Name})
+	assert.Equal(t, "missing", n.Children[1].Value)
+
+	type Pair struct{ A, B int }
+	d := NewDumper(WithTypeExpander(reflect.TypeOf(Pair{}), func(v reflect.Value) []Child {
+		return []Child{{Label: "sum", Value: v.Field(0).Int() + v.Field(1).Int()}}
+	}))
+	n = d.Inspect(Pair{A: 1, B: 2})
+	assert.Equal(t, NodeExpanded, n.Kind)
+	assert.Equal(t, "sum", n.Children[0].Name)
+	assert.Equal(t, "3", n.Children[0].Value)
+}
+
+// TestInspectCustomRenderer walks the tree the way a custom renderer would.
+func TestInspectCustomRenderer(t *testing.T) {
+	var render func(sb *strings.Builder, n *Node)
+	render = func(sb *strings.Builder, n *Node) {
+		switch n.Kind {
+		case NodeStruct, NodeMap, NodeList:
+			sb.WriteString("(")
+			for i, c := range n.Children {
+				if i > 0 {
+					sb.WriteString(" ")
+				}
+				sb.WriteString(c.Name + "=")
+				render(sb, c)
+			}
+			sb.WriteString(")")
+		case NodeInterface:
+			render(sb, n.Children[0])
+		default:
+			sb.WriteString(n.Value)
+		}
+	}
+
+	var sb strings.Builder
+	render(&sb, NewDumper(WithExcludeFields("Password", "Friend")).Inspect(inspectUser{Name: "Al", Tags: []string{"x"}, Meta: map[string]int{"k": 2}}))
+	assert.Equal(t, "(Name=Al Tags=(0=x) Meta=(k=2) score=0x0)", sb.String())
+}
+
+func TestInspectMatchesStreamedDump(t *testing.T) {
+	u := &inspectUser{Name: "Alice", Tags: []string{"a", "b"}, Meta: map[string]int{"x": 1, "y": 2}, score: 3}
+	u.Friend = u
+	v := []any{u, map[string]any{"list": []int{1, 2, 3}, "user": u}, [2]string{"p", "q"}}
+	for _, d := range []*Dumper{
+		newDumperT(t, WithoutHeader()),
+		newDumperT(t, WithoutHeader(), WithCompact(40)),
+		newDumperT(t, WithoutHeader(), WithCompact(200), WithMaxItems(2)),
+		newDumperT(t, WithoutHeader(), WithMaxNodes(9)),
+	} {
+		nodes := d.inspectValues([]any{v})
+		var sb strings.Builder
+		d.printNode(&sb, nodes[0], 0)
+		streamed := d.DumpStr(v)
+		assert.True(t, strings.HasPrefix(streamed, sb.String()+"\n"))
+	}
+}
+
+func TestInspectMapBehindUnexportedField(t *testing.T) {
+	type inner struct {
+		m map[string]int
+	}
+	v := map[string]inner{"a": {m: map[string]int{"x": 1}}}
+	d := newDumperT(t, WithoutHeader())
+
+	n := d.Inspect(v)
+	assert.Equal(t, "x", n.Children[0].Children[0].Children[0].Name)
+
+	assert.Contains(t, d.DumpStr(v), "-m => #map[string]int {\n       x => 1 #int\n    }")
+	assert.Contains(t, newDumperT(t, WithJSONMode(JSONTyped)).DumpJSONStr(v), `"value": "x"`)
+	assert.Contains(t, d.DiffStr(v, map[string]inner{"a": {m: map[string]int{"x": 2}}}), "x => 2 #int")
+	assert.Contains(t, d.DumpGoStr(v), `"x": 1`)
+}
@@ -0,0 +1,146 @@
+package godump
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// IntBaseDecimal prints integers in base 10 (the default).
+	IntBaseDecimal IntBase = iota
+	// IntBaseHex prints integers in base 16 with a 0x prefix.
+	IntBaseHex
+	// IntBaseOctal prints integers in base 8 with a 0o prefix.
+	IntBaseOctal
+	// IntBaseBinary prints integers in base 2 with a 0b prefix.
+	IntBaseBinary
+)
+
+// IntBase selects the base used to print integers.
+type IntBase int
+
+// NumberFormat controls how integers, floats and complex numbers are printed.
+// Floats always use the shortest representation that round-trips, and NaN,
+// +Inf, -Inf and -0 are spelled out.
+type NumberFormat struct {
+	// IntBase selects decimal, hexadecimal, octal or binary integers.
+	IntBase IntBase
+	// Separator groups digits when non-empty: thousands for decimal numbers,
+	// groups of four digits for hex and binary, and three for octal.
+	Separator string
+}
+
+// WithNumberFormat sets how numbers are printed.
+// @group Options
+//
+// Example: print integers as hex with separators
+//
+//	// Default: decimal, no separators
+//	d := godump.NewDumper(godump.WithNumberFormat(godump.NumberFormat{
+//		IntBase:   godump.IntBaseHex,
+//		Separator: "_",
+//	}))
+//	d.Dump(3735928559)
+//	// 0xdead_beef #int
+func WithNumberFormat(f NumberFormat) Option {
+	return func(d *Dumper) *Dumper {
+		d.numberFormat = f
+		return d
+	}
+}
+
+// formatInt renders a signed integer using the configured number format.
+func (d *Dumper) formatInt(n int64) string {
+	if n < 0 {
+		// Negate in unsigned space so math.MinInt64 does not overflow.
+		return "-" + d.formatUint(uint64(-(n+1))+1)
+	}
+	return d.formatUint(uint64(n))
+}
+
+// formatUint renders an unsigned integer using the configured number format.
+func (d *Dumper) formatUint(n uint64) string {
+	prefix, base, group := "", 10, 3
+	switch d.numberFormat.IntBase {
+	case IntBaseHex:
+		prefix, base, group = "0x", 16, 4
+	case IntBaseOctal:
+		prefix, base, group = "0o", 8, 3
+	case IntBaseBinary:
+		prefix, base, group = "0b", 2, 4
+	}
+	return prefix + groupDigits(strconv.FormatUint(n, base), group, d.numberFormat.Separator)
+}
+
+// formatFloat renders the shortest representation of f that parses back to
+// the same value, using exponent notation only for very small or large magnitudes.
+func (d *Dumper) formatFloat(f float64, bits int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+
+	abs := math.Abs(f)
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		return strconv.FormatFloat(f, 'e', -1, bits)
+	}
+
+	s := strconv.FormatFloat(f, 'f', -1, bits)
+	if d.numberFormat.Separator == "" {
+		return s
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	s = sign + groupDigits(whole, 3, d.numberFormat.Separator)
+	if hasFrac {
+		s += "." + frac
+	}
+	return s
+}
+
+// formatComplex renders a complex number as (real±imag i) using formatFloat for both parts.
+func (d *Dumper) formatComplex(c complex128, bits int) string {
+	re := d.formatFloat(real(c), bits)
+	im := d.formatFloat(imag(c), bits)
+	if !strings.HasPrefix(im, "-") && !strings.HasPrefix(im, "+") {
+		im = "+" + im
+	}
+	return "(" + re + im + "i)"
+}
+
+// groupDigits inserts sep between groups of size digits, counting from the right.
+func groupDigits(digits string, size int, sep string) string {
+	if sep == "" || len(digits) <= size {
+		return digits
+	}
+
+	var sb strings.Builder
+	first := len(digits) % size
+	if first > 0 {
+		sb.WriteString(digits[:first])
+	}
+	for i := first; i < len(digits); i += size {
+		if sb.Len() > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(digits[i : i+size])
+	}
+	return sb.String()
+}
+
+// floatBits returns the bit size of a float or complex kind's components.
+func floatBits(is32 bool) int {
+	if is32 {
+		return 32
+	}
+	return 64
+}
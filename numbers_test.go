@@ -0,0 +1,80 @@
+package godump
+
+import (
+	"math"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+func TestFloatsRoundTrip(t *testing.T) {
+	d := newDumperT(t, WithoutHeader())
+
+	assert.Equal(t, "1e-09 #float64\n", d.DumpStr(1e-9))
+	assert.Equal(t, "0.1 #float32\n", d.DumpStr(float32(0.1)))
+	assert.Equal(t, "123456789.12345679 #float64\n", d.DumpStr(123456789.123456789))
+	assert.Equal(t, "1e+21 #float64\n", d.DumpStr(1e21))
+	assert.Equal(t, "100 #float64\n", d.DumpStr(100.0))
+	assert.Equal(t, "0 #float64\n", d.DumpStr(0.0))
+}
+
+func TestFloatSpecialValues(t *testing.T) {
+	d := newDumperT(t, WithoutHeader())
+
+	assert.Equal(t, "NaN #float64\n", d.DumpStr(math.NaN()))
+	assert.Equal(t, "+Inf #float64\n", d.DumpStr(math.Inf(1)))
+	assert.Equal(t, "-Inf #float32\n", d.DumpStr(float32(math.Inf(-1))))
+	assert.Equal(t, "-0 #float64\n", d.DumpStr(math.Copysign(0, -1)))
+}
+
+func TestComplexFormatting(t *testing.T) {
+	d := newDumperT(t, WithoutHeader())
+
+	assert.Equal(t, "(1.1+2.2i) #complex128\n", d.DumpStr(complex(1.1, 2.2)))
+	assert.Equal(t, "(0.1-1e-09i) #complex64\n", d.DumpStr(complex64(complex(0.1, -1e-9))))
+	assert.Equal(t, "(NaN+Infi) #complex128\n", d.DumpStr(complex(math.NaN(), math.Inf(1))))
+}
+
+func TestNumberFormatIntBases(t *testing.T) {
+	tests := []struct {
+		name   string
+		format NumberFormat
+		value  any
+		want   string
+	}{
+		{name: "decimal", format: NumberFormat{}, value: -42, want: "-42"},
+		{name: "hex", format: NumberFormat{IntBase: IntBaseHex}, value: 255, want: "0xff"},
+		{name: "negative hex", format: NumberFormat{IntBase: IntBaseHex}, value: int8(-128), want: "-0x80"},
+		{name: "octal", format: NumberFormat{IntBase: IntBaseOctal}, value: uint(8), want: "0o10"},
+		{name: "binary", format: NumberFormat{IntBase: IntBaseBinary}, value: uint8(5), want: "0b101"},
+		{name: "min int64", format: NumberFormat{}, value: int64(math.MinInt64), want: "-9223372036854775808"},
+		{name: "thousands", format: NumberFormat{Separator: ","}, value: 1234567, want: "1,234,567"},
+		{name: "short", format: NumberFormat{Separator: ","}, value: 123, want: "123"},
+		{name: "hex groups", format: NumberFormat{IntBase: IntBaseHex, Separator: "_"}, value: uint32(0xdeadbeef), want: "0xdead_beef"},
+		{name: "binary groups", format: NumberFormat{IntBase: IntBaseBinary, Separator: "_"}, value: 0x1ff, want: "0b1_1111_1111"},
+		{name: "float separators", format: NumberFormat{Separator: ","}, value: -1234567.25, want: "-1,234,567.25"},
+		{name: "float without fraction", format: NumberFormat{Separator: ","}, value: 1000.0, want: "1,000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := newDumperT(t, WithoutHeader(), WithNumberFormat(tt.format)).DumpStr(tt.value)
+			assert.Contains(t, out, tt.want+" #")
+		})
+	}
+}
+
+func TestNumberFormatInGoLiterals(t *testing.T) {
+	d := newDumperT(t, WithNumberFormat(NumberFormat{IntBase: IntBaseHex, Separator: "_"}))
+	assert.Equal(t, "0x7ead_beef", d.DumpGoStr(0x7eadbeef))
+
+	d = newDumperT(t, WithNumberFormat(NumberFormat{Separator: ","}))
+	assert.Equal(t, "1234567", d.DumpGoStr(1234567))
+}
+
+func TestGroupDigits(t *testing.T) {
+	assert.Equal(t, "1234", groupDigits("1234", 3, ""))
+	assert.Equal(t, "123", groupDigits("123", 3, ","))
+	assert.Equal(t, "123,456", groupDigits("123456", 3, ","))
+	assert.Equal(t, "1 2345", groupDigits("12345", 4, " "))
+}
@@ -0,0 +1,646 @@
+package godump
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParsedDump is a dump read back from text by [Parse].
+type ParsedDump struct {
+	// File and Line are the call site from the "<#dump // file:line" header.
+	// They are empty for values printed without a header.
+	File string
+	Line int
+	// Goroutine is the goroutine id shown by WithGoroutineID, or 0.
+	Goroutine uint64
+	// Values are the dumped values, in order.
+	Values []*Node
+}
+
+var (
+	dumpHeaderRe  = regexp.MustCompile(`<#dump // (.+?):(\d+)(?: \[goroutine (\d+)\])?(?:\s.*)?$`)
+	addrTagRe     = regexp.MustCompile(`(?: @(0x[0-9a-f]+(?: → 0x[0-9a-f]+)*))?(?: len=(\d+) cap=(\d+))?(?: aliases &(\d+))?$`)
+	blockHeaderRe = regexp.MustCompile(`^#(.+?)((?: @0x[0-9a-f]+(?: → 0x[0-9a-f]+)*)?(?: len=\d+ cap=\d+)?(?: aliases &\d+)?) ([{[])$`)
+	hexHeaderRe   = regexp.MustCompile(`^\(\[\]uint8\) \(len=(\d+) cap=(\d+)\)(.*) \{$`)
+	chanRe        = regexp.MustCompile(`^(.+)\((0x[0-9a-f]+)\) dir=(\w+) elem=(.+?) len=(\d+) cap=(\d+)( full)?( \(buffer unavailable\))?( closed)?( \.\.\. \(max depth\))?( \[)?$`)
+	funcRe        = regexp.MustCompile(`^(.+?)(?: \((closure|method value)\))?(?: (\S+:\d+))? #(\*?func\(.*)$`)
+	anchorRe      = regexp.MustCompile(`^&(\d+) `)
+	backRefRe     = regexp.MustCompile(`^↩\x{FE0E}?(?: &(\d+)| #(.+))$`)
+	truncatedRe   = regexp.MustCompile(`^\.\.\. \(truncated\)\s*([}\]]?)$`)
+)
+
+// Parse reads text written by Dump, DumpStr or Fdump, plain or with ANSI
+// colors, back into node trees, one [ParsedDump] per "<#dump" header. Lines
+// that are not part of a dump, such as other log output, and Diff output are
+// skipped.
+//
+// Nodes hold the text as printed: strings and labels keep the escapes added
+// for control and invisible characters, and a string ending in "…" is taken
+// as cut by WithMaxStringLen. Compact single-line values are read on a best
+// effort basis, and their elements only have a Type when it can be derived
+// from the container's type. The returned nodes can be passed to Dump,
+// DumpHTML, DumpJSON or Diff to render them again.
+// @group Inspect
+//
+// Example: read a dump from a log
+//
+//	log := "<#dump // main.go:12\n#main.User {\n  +Name => \"Alice\" #string\n}\n"
+//	dumps, err := godump.Parse(log)
+//	if err != nil {
+//		panic(err)
+//	}
+//	user := dumps[0].Values[0]
+//	fmt.Println(dumps[0].File, user.Type, user.Children[0].Value)
+//	// main.go main.User Alice
+func Parse(text string) ([]ParsedDump, error) {
+	p := &dumpParser{lines: splitLines(stripANSI(text))}
+	var dumps []ParsedDump
+	inDiff := false
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		if m := dumpHeaderRe.FindStringSubmatch(line); m != nil {
+			dump := ParsedDump{File: m[1]}
+			dump.Line, _ = strconv.Atoi(m[2])
+			dump.Goroutine, _ = strconv.ParseUint(m[3], 10, 64)
+			dumps = append(dumps, dump)
+			inDiff = false
+			continue
+		}
+		if strings.Contains(line, "<#diff // ") {
+			inDiff = true
+			continue
+		}
+		if inDiff || !looksLikeValue(line) {
+			continue
+		}
+
+		n, err := p.parseValue(line, 0)
+		if err != nil {
+			return dumps, err
+		}
+		if len(dumps) == 0 {
+			dumps = append(dumps, ParsedDump{})
+		}
+		last := &dumps[len(dumps)-1]
+		last.Values = append(last.Values, n)
+	}
+	return dumps, nil
+}
+
+// dumpParser reads values from the lines of a dump.
+type dumpParser struct {
+	lines []string
+	// pos is the line being read. After a value is parsed it is the value's
+	// last line.
+	pos int
+}
+
+// looksLikeValue reports whether a line at the top level starts a value.
+func looksLikeValue(line string) bool {
+	if line == "" || line[0] == ' ' {
+		return false
+	}
+	if strings.Contains(line, " #") || strings.HasPrefix(line, "#") || strings.HasSuffix(line, "(nil)") {
+		return true
+	}
+	for _, prefix := range []string{"<invalid>", "<redacted>", "... (max depth)", "↩", "&", "([]uint8) ", "unsafe.Pointer("} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return chanRe.MatchString(line)
+}
+
+// parseValue parses the value starting with text on the current line, which
+// is printed at the given indent level.
+func (p *dumpParser) parseValue(text string, indent int) (*Node, error) {
+	id := 0
+	if m := anchorRe.FindStringSubmatch(text); m != nil {
+		id, _ = strconv.Atoi(m[1])
+		text = text[len(m[0]):]
+	}
+	n, err := p.parseBody(text, indent)
+	if err != nil {
+		return nil, err
+	}
+	n.ID = id
+	return n, nil
+}
+
+// parseBody parses a value without its anchor.
+func (p *dumpParser) parseBody(text string, indent int) (*Node, error) {
+	if n := parseMarker(text); n != nil {
+		return n, nil
+	}
+	if m := hexHeaderRe.FindStringSubmatch(text); m != nil {
+		return p.parseHexDump(m, indent)
+	}
+	if m := chanRe.FindStringSubmatch(text); m != nil {
+		return p.parseChan(m, indent)
+	}
+	if m := blockHeaderRe.FindStringSubmatch(text); m != nil {
+		n := &Node{Type: m[1]}
+		applyAddrTag(n, m[2])
+		if m[3] == "[" {
+			n.Kind = NodeList
+			n.slice = strings.HasPrefix(strings.TrimLeft(n.Type, "*"), "[]")
+		} else {
+			n.Kind = p.blockKind(n.Type, indent)
+		}
+		return n, p.parseChildren(n, indent)
+	}
+	if isInline(text) {
+		return parseInline(text, ""), nil
+	}
+	if m := funcRe.FindStringSubmatch(text); m != nil && !strings.HasPrefix(text, `"`) {
+		return p.parseFunc(m, indent)
+	}
+	// Closure captures have a type but no value.
+	if strings.HasPrefix(text, "#") {
+		return &Node{Kind: NodeText, Type: text[1:]}, nil
+	}
+	return parseScalar(text, ""), nil
+}
+
+// parseMarker parses the single-line forms that are not scalars: invalid and
+// nil values, back-references, redacted and max depth placeholders and
+// unsafe pointers. It returns nil for anything else.
+func parseMarker(text string) *Node {
+	switch {
+	case text == "<invalid>":
+		return &Node{Kind: NodeInvalid}
+	case text == "... (max depth)":
+		return &Node{Kind: NodeInvalid, MaxDepth: true}
+	case strings.HasPrefix(text, "<redacted>"):
+		typ := strings.TrimPrefix(strings.TrimPrefix(text, "<redacted>"), " #")
+		return &Node{Kind: kindOfTypeName(typ), Type: typ, Redacted: true}
+	case strings.HasPrefix(text, "unsafe.Pointer(") && strings.HasSuffix(text, ")"):
+		return &Node{Kind: NodeUnsafePointer, Type: "unsafe.Pointer", Value: text[len("unsafe.Pointer(") : len(text)-1]}
+	case strings.HasSuffix(text, "(nil)") && !strings.Contains(text, " #"):
+		return &Node{Kind: NodeNil, Type: strings.TrimSuffix(text, "(nil)")}
+	}
+	if m := backRefRe.FindStringSubmatch(text); m != nil {
+		ref, _ := strconv.Atoi(m[1])
+		return &Node{Kind: NodeRef, Type: m[2], Ref: ref}
+	}
+	return nil
+}
+
+// blockKind tells structs, maps and labeled blocks apart by their type and
+// first entry.
+func (p *dumpParser) blockKind(typ string, indent int) NodeKind {
+	if strings.HasPrefix(strings.TrimLeft(typ, "*"), "map[") {
+		return NodeMap
+	}
+	prefix := strings.Repeat(" ", (indent+1)*indentWidth)
+	if p.pos+1 >= len(p.lines) || !strings.HasPrefix(p.lines[p.pos+1], prefix) {
+		return NodeStruct
+	}
+	if next := p.lines[p.pos+1][len(prefix):]; strings.HasPrefix(next, "+") || strings.HasPrefix(next, "-") {
+		return NodeStruct
+	}
+	return NodeExpanded
+}
+
+// parseChildren reads the entries of a struct, map, list or labeled block
+// that opens on the current line, up to its closing bracket.
+func (p *dumpParser) parseChildren(n *Node, indent int) error {
+	start := p.pos
+	closeChar := "}"
+	if n.Kind == NodeList || n.Kind == NodeChan {
+		closeChar = "]"
+	}
+	prefix := strings.Repeat(" ", (indent+1)*indentWidth)
+	closing := strings.Repeat(" ", indent*indentWidth) + closeChar
+
+	for p.pos++; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		if strings.HasPrefix(line, closing) {
+			applyAddrTag(n, line[len(closing):])
+			p.finishBlock(n)
+			return nil
+		}
+		if !strings.HasPrefix(line, prefix) {
+			return fmt.Errorf("godump: line %d: expected an entry of the block opened on line %d", p.pos+1, start+1)
+		}
+
+		body := line[len(prefix):]
+		if m := truncatedRe.FindStringSubmatch(body); m != nil {
+			n.Truncated = true
+			if m[1] != "" {
+				p.finishBlock(n)
+				return nil
+			}
+			continue
+		}
+
+		unexported := false
+		switch n.Kind {
+		case NodeStruct:
+			if !strings.HasPrefix(body, "+") && !strings.HasPrefix(body, "-") {
+				return fmt.Errorf("godump: line %d: expected \"label => value\"", p.pos+1)
+			}
+			unexported = body[0] == '-'
+			body = body[1:]
+		case NodeMap:
+			body = strings.TrimPrefix(body, " ")
+		}
+		label, value, ok := strings.Cut(body, " => ")
+		if !ok {
+			return fmt.Errorf("godump: line %d: expected \"label => value\"", p.pos+1)
+		}
+		if n.Kind != NodeMap {
+			label = strings.TrimRight(label, " ")
+		}
+
+		child, err := p.parseValue(value, indent+1)
+		if err != nil {
+			return err
+		}
+		child.Name = label
+		child.Unexported = unexported
+		n.Children = append(n.Children, child)
+	}
+	return fmt.Errorf("godump: line %d: block is not closed", start+1)
+}
+
+// finishBlock fills in what a block's entries imply: lengths, map keys and
+// the message of errors.
+func (p *dumpParser) finishBlock(n *Node) {
+	switch n.Kind {
+	case NodeList:
+		if n.Len == 0 && !n.Truncated {
+			n.Len = len(n.Children)
+		}
+	case NodeMap:
+		n.Len = len(n.Children)
+		keyType, _ := mapTypes(n.Type)
+		for _, e := range n.Children {
+			e.Key = parseScalar(e.Name, keyType)
+			if keyType == "string" {
+				e.Key = &Node{Kind: NodeString, Type: keyType, Value: e.Name, Raw: e.Name}
+			}
+		}
+	case NodeExpanded:
+		if isErrorBlock(n) {
+			n.Kind = NodeError
+			n.Value = n.Children[0].Value
+			n.Truncated = n.Children[0].Truncated
+		}
+	}
+}
+
+// isErrorBlock reports whether a labeled block holds the parts of an error.
+func isErrorBlock(n *Node) bool {
+	if len(n.Children) == 0 || n.Children[0].Name != "Error" || n.Children[0].Kind != NodeString {
+		return false
+	}
+	for _, c := range n.Children[1:] {
+		if c.Name != "Unwrap" && c.Name != "Stack" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseHexDump reads the lines of a byte slice hex dump.
+func (p *dumpParser) parseHexDump(m []string, indent int) (*Node, error) {
+	start := p.pos
+	n := &Node{Kind: NodeBytes, Type: "[]uint8"}
+	n.Len, _ = strconv.Atoi(m[1])
+	n.Cap, _ = strconv.Atoi(m[2])
+	applyAddrTag(n, m[3])
+
+	closing := strings.Repeat(" ", indent*indentWidth) + "}"
+	data := make([]byte, 0, n.Len)
+	for p.pos++; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		if line == closing {
+			n.Bytes = data
+			return n, nil
+		}
+		hexPart, _, _ := strings.Cut(line, "|")
+		fields := strings.Fields(hexPart)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("godump: line %d: expected a hex dump line", p.pos+1)
+		}
+		for _, f := range fields[1:] {
+			b, err := strconv.ParseUint(f, 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("godump: line %d: invalid byte %q", p.pos+1, f)
+			}
+			data = append(data, byte(b))
+		}
+	}
+	return nil, fmt.Errorf("godump: line %d: hex dump is not closed", start+1)
+}
+
+// parseChan reads a channel and the buffered elements that follow it.
+func (p *dumpParser) parseChan(m []string, indent int) (*Node, error) {
+	n := &Node{Kind: NodeChan, Type: m[1], Value: m[2], Note: m[3], elemType: m[4]}
+	n.Len, _ = strconv.Atoi(m[5])
+	n.Cap, _ = strconv.Atoi(m[6])
+	n.noBuffer = m[8] != ""
+	n.Closed = m[9] != ""
+	n.MaxDepth = m[10] != ""
+	if m[11] == "" {
+		return n, nil
+	}
+	return n, p.parseChildren(n, indent)
+}
+
+// parseFunc reads a function and the captures that may follow it.
+func (p *dumpParser) parseFunc(m []string, indent int) (*Node, error) {
+	n := &Node{Kind: NodeFunc, Value: m[1], Note: m[2], Location: m[3], Type: m[4]}
+	switch {
+	case strings.HasSuffix(n.Type, " (captures unavailable)"):
+		n.Type = strings.TrimSuffix(n.Type, " (captures unavailable)")
+		n.noCaptures = true
+	case strings.HasSuffix(n.Type, " ... (max depth)"):
+		n.Type = strings.TrimSuffix(n.Type, " ... (max depth)")
+		n.MaxDepth = true
+	case strings.HasSuffix(n.Type, " {"):
+		n.Type = strings.TrimSuffix(n.Type, " {")
+		n.Kind = NodeExpanded
+		err := p.parseChildren(n, indent)
+		n.Kind = NodeFunc
+		return n, err
+	}
+	return n, nil
+}
+
+// parseScalar reads a string, number, bool or Stringer text, followed by its
+// type and address tag when present. typ is the type to assume when none is
+// printed.
+func parseScalar(text, typ string) *Node {
+	if n := parseMarker(text); n != nil {
+		return n
+	}
+
+	n := &Node{Type: typ}
+	loc := addrTagRe.FindStringIndex(text)
+	applyAddrTag(n, text[loc[0]:])
+	value := text[:loc[0]]
+	if i := strings.LastIndex(value, " #"); i >= 0 {
+		value, n.Type = value[:i], value[i+2:]
+	}
+
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		n.Kind = NodeString
+		n.Value = value[1 : len(value)-1]
+		if cut := strings.TrimSuffix(n.Value, "…"); cut != n.Value {
+			n.Value, n.Truncated = cut, true
+		} else {
+			n.Raw = n.Value
+		}
+		return n
+	}
+
+	n.Value = value
+	if raw, ok := parseNumber(value, n.Type); ok {
+		n.Kind, n.Raw = NodeScalar, raw
+	} else {
+		n.Kind = NodeText
+	}
+	return n
+}
+
+// parseNumber converts a printed bool or number to the raw value Inspect
+// would give it. Digit separators are removed first.
+func parseNumber(s, typ string) (any, bool) {
+	if s == "true" || s == "false" {
+		return s == "true", true
+	}
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, "i)") {
+		c, err := strconv.ParseComplex(s, 128)
+		if err != nil {
+			return nil, false
+		}
+		if typ == "complex64" {
+			return complex64(c), true
+		}
+		return c, true
+	}
+	if s == "" || !strings.ContainsAny(s[:1], "+-0123456789NI") {
+		return nil, false
+	}
+
+	digits := strings.NewReplacer("_", "", ",", "", "'", "", " ", "").Replace(s)
+	switch {
+	case strings.HasPrefix(typ, "uint"):
+		u, err := strconv.ParseUint(digits, 0, 64)
+		return u, err == nil
+	case strings.HasPrefix(typ, "int"):
+		i, err := strconv.ParseInt(digits, 0, 64)
+		return i, err == nil
+	case typ == "float32":
+		f, err := strconv.ParseFloat(digits, 32)
+		return float32(f), err == nil
+	}
+	if i, err := strconv.ParseInt(digits, 0, 64); err == nil {
+		return i, true
+	}
+	if u, err := strconv.ParseUint(digits, 0, 64); err == nil {
+		return u, true
+	}
+	f, err := strconv.ParseFloat(digits, 64)
+	return f, err == nil
+}
+
+// applyAddrTag records the addresses, slice length and capacity, and alias
+// of an address tag on n.
+func applyAddrTag(n *Node, tag string) {
+	m := addrTagRe.FindStringSubmatch(tag)
+	if m == nil {
+		return
+	}
+	if m[1] != "" {
+		for _, a := range strings.Split(m[1], " → ") {
+			if addr, err := strconv.ParseUint(a, 0, 64); err == nil {
+				n.Addrs = append(n.Addrs, uintptr(addr))
+			}
+		}
+	}
+	if m[2] != "" {
+		n.Len, _ = strconv.Atoi(m[2])
+		n.Cap, _ = strconv.Atoi(m[3])
+	}
+	if m[4] != "" {
+		n.Alias, _ = strconv.Atoi(m[4])
+	}
+}
+
+// isInline reports whether text is a compact single-line container.
+func isInline(text string) bool {
+	if text == "" || !strings.ContainsAny(text[:1], "#[{") {
+		return false
+	}
+	return inlineBodyStart(text) >= 0
+}
+
+// inlineBodyStart returns the index of the bracket that opens the group
+// closing at the end of s, or -1 if s does not end with a bracketed group.
+func inlineBodyStart(s string) int {
+	depth, start, quoted := 0, -1, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '[' || c == '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case c == ']' || c == '}':
+			depth--
+			if depth == 0 && i == len(s)-1 {
+				return start
+			}
+		}
+	}
+	return -1
+}
+
+// parseInline reads a compact single-line container. typ is its type when
+// the text does not start with one.
+func parseInline(text, typ string) *Node {
+	open := inlineBodyStart(text)
+	if open > 0 {
+		typ = strings.TrimSuffix(text[1:open], " ")
+	}
+	body := text[open+1 : len(text)-1]
+
+	n := &Node{Type: typ, Kind: NodeStruct}
+	keyType, elemType := mapTypes(typ)
+	switch {
+	case text[open] == '[':
+		n.Kind = NodeList
+		n.slice = strings.HasPrefix(strings.TrimLeft(typ, "*"), "[]")
+		elemType = listElemType(typ)
+	case strings.HasPrefix(strings.TrimLeft(typ, "*"), "map["):
+		n.Kind = NodeMap
+	}
+
+	for _, item := range splitInline(body) {
+		if item == "... (truncated)" {
+			n.Truncated = true
+			continue
+		}
+		label, value := strconv.Itoa(len(n.Children)), item
+		if n.Kind != NodeList {
+			label, value, _ = strings.Cut(item, ": ")
+		}
+
+		var child *Node
+		if isInline(value) {
+			child = parseInline(value, elemType)
+		} else {
+			child = parseScalar(value, elemType)
+		}
+		if child.Type != "" && child.Type != elemType && !child.Redacted && child.Kind != NodeNil {
+			// Elements only carry a type of their own inside an interface.
+			child = &Node{Kind: NodeInterface, Type: elemType, Children: []*Node{child}}
+		}
+		if n.Kind == NodeStruct && strings.HasPrefix(label, "-") {
+			label, child.Unexported = label[1:], true
+		}
+		if n.Kind == NodeMap {
+			child.Key = parseScalar(label, keyType)
+			if keyType == "string" {
+				child.Key = &Node{Kind: NodeString, Type: keyType, Value: label, Raw: label}
+			}
+		}
+		child.Name = label
+		n.Children = append(n.Children, child)
+	}
+	n.Len = len(n.Children)
+	return n
+}
+
+// splitInline splits the body of a compact container at the top-level ", "
+// separators.
+func splitInline(s string) []string {
+	var items []string
+	depth, quoted, start := 0, false, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0 && strings.HasPrefix(s[i:], ", "):
+			items = append(items, s[start:i])
+			start = i + 2
+			i++
+		}
+	}
+	if start < len(s) {
+		items = append(items, s[start:])
+	}
+	return items
+}
+
+// mapTypes returns the key and element types of a map type name.
+func mapTypes(typ string) (string, string) {
+	base := strings.TrimLeft(typ, "*")
+	if !strings.HasPrefix(base, "map[") {
+		return "", ""
+	}
+	depth := 0
+	for i := len("map"); i < len(base); i++ {
+		switch base[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return base[len("map["):i], base[i+1:]
+			}
+		}
+	}
+	return "", ""
+}
+
+// listElemType returns the element type of a slice or array type name.
+func listElemType(typ string) string {
+	base := strings.TrimLeft(typ, "*")
+	if !strings.HasPrefix(base, "[") {
+		return ""
+	}
+	if i := strings.Index(base, "]"); i >= 0 {
+		return base[i+1:]
+	}
+	return ""
+}
+
+// kindOfTypeName guesses the node kind for a printed type name.
+func kindOfTypeName(typ string) NodeKind {
+	base := strings.TrimLeft(typ, "*")
+	switch {
+	case base == "string":
+		return NodeString
+	case base == "[]uint8":
+		return NodeBytes
+	case strings.HasPrefix(base, "map["):
+		return NodeMap
+	case strings.HasPrefix(base, "["):
+		return NodeList
+	case strings.HasPrefix(base, "chan ") || strings.HasPrefix(base, "<-chan "):
+		return NodeChan
+	case strings.HasPrefix(base, "func("):
+		return NodeFunc
+	case base == "bool" || strings.HasPrefix(base, "int") || strings.HasPrefix(base, "uint") ||
+		strings.HasPrefix(base, "float") || strings.HasPrefix(base, "complex"):
+		return NodeScalar
+	case strings.HasPrefix(base, "interface {"):
+		return NodeInterface
+	default:
+		return NodeStruct
+	}
+}
@@ -0,0 +1,168 @@
+package godump
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+type parseOrder struct {
+	ID      int
+	Items   []string
+	Prices  map[string]float64
+	Payload []byte
+	Err     error
+	Any     any
+	secret  string
+	Self    *parseOrder
+}
+
+func newParseOrder() *parseOrder {
+	o := &parseOrder{
+		ID:      7,
+		Items:   []string{"apple", "pear", "plum"},
+		Prices:  map[string]float64{"apple": 1.5, "pear": 2},
+		Payload: []byte("godump parses\x00\x01"),
+		Err:     fmt.Errorf("checkout: %w", errors.New("declined")),
+		Any:     uint8(3),
+		secret:  "tab\there",
+	}
+	o.Self = o
+	return o
+}
+
+// stripDumpHeader drops the header line of a dump.
+func stripDumpHeader(s string) string {
+	if first, rest, _ := strings.Cut(s, "\n"); strings.Contains(first, "<#dump") {
+		return rest
+	}
+	return s
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	o := newParseOrder()
+	ansi := NewDumper()
+	ansi.colorizer = colorizeANSI
+	dumpers := map[string]*Dumper{
+		"plain":     NewDumper(WithoutColor()),
+		"ansi":      ansi,
+		"compact":   NewDumper(WithoutColor(), WithCompact(60)),
+		"truncated": NewDumper(WithoutColor(), WithMaxItems(1), WithMaxStringLen(3), WithMaxDepth(2)),
+		"addresses": NewDumper(WithoutColor(), WithAddresses(), WithRedactFields("secret")),
+		"numbers":   NewDumper(WithoutColor(), WithNumberFormat(NumberFormat{IntBase: IntBaseHex, Separator: "_"})),
+	}
+	for name, d := range dumpers {
+		out := d.DumpStr(o, 42, "s")
+		dumps, err := Parse(out)
+		assert.NoError(t, err, name)
+		assert.Equal(t, 1, len(dumps), name)
+		assert.Equal(t, 3, len(dumps[0].Values), name)
+
+		again := d.clone()
+		again.disableHeader = true
+		vs := []any{dumps[0].Values[0], dumps[0].Values[1], dumps[0].Values[2]}
+		assert.Equal(t, stripDumpHeader(out), again.DumpStr(vs...), name)
+	}
+}
+
+func TestParseNodes(t *testing.T) {
+	out := NewDumper(WithoutColor(), WithGoroutineID()).DumpStr(newParseOrder())
+	dumps, err := Parse("2026/01/02 10:00:00 " + out)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(dumps[0].File, "parse_test.go"))
+	assert.True(t, dumps[0].Line > 0)
+	assert.True(t, dumps[0].Goroutine > 0)
+
+	n := dumps[0].Values[0]
+	assert.Equal(t, NodeStruct, n.Kind)
+	assert.Equal(t, "*godump.parseOrder", n.Type)
+	assert.Equal(t, 1, n.ID)
+
+	names := make([]string, len(n.Children))
+	for i, c := range n.Children {
+		names[i] = c.Name
+	}
+	assert.Equal(t, []string{"ID", "Items", "Prices", "Payload", "Err", "Any", "secret", "Self"}, names)
+
+	assert.Equal(t, int64(7), n.Children[0].Raw)
+	items := n.Children[1]
+	assert.Equal(t, NodeList, items.Kind)
+	assert.Equal(t, 3, items.Len)
+	assert.Equal(t, "pear", items.Children[1].Value)
+
+	prices := n.Children[2]
+	assert.Equal(t, NodeMap, prices.Kind)
+	assert.Equal(t, "apple", prices.Children[0].Key.Raw)
+	assert.Equal(t, 1.5, prices.Children[0].Raw)
+
+	assert.Equal(t, []byte("godump parses\x00\x01"), n.Children[3].Bytes)
+
+	errNode := n.Children[4]
+	assert.Equal(t, NodeError, errNode.Kind)
+	assert.Equal(t, "checkout: declined", errNode.Value)
+	assert.Equal(t, "declined", errNode.Children[1].Value)
+
+	assert.Equal(t, uint64(3), n.Children[5].Raw)
+	assert.True(t, n.Children[6].Unexported)
+	assert.Equal(t, `tab\there`, n.Children[6].Value)
+	assert.Equal(t, NodeRef, n.Children[7].Kind)
+	assert.Equal(t, 1, n.Children[7].Ref)
+}
+
+func TestParseLogsAndMarkers(t *testing.T) {
+	d := NewDumper(WithoutColor())
+	log := "starting\n" + d.DumpStr([]int{1, 2}) + "request done\n" +
+		d.DiffStr(1, 2) + d.DumpStr(map[string]int{"a": 1, "b": 2}, nil)
+	dumps, err := Parse(log)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(dumps))
+	assert.Equal(t, 1, len(dumps[0].Values))
+	assert.Equal(t, 2, len(dumps[1].Values))
+
+	dumps, err = Parse(NewDumper(WithoutColor(), WithoutHeader(), WithMaxItems(1), WithMaxDepth(1)).DumpStr(map[string][]int{"a": {1}, "b": {2}}))
+	assert.NoError(t, err)
+	m := dumps[0].Values[0]
+	assert.True(t, m.Truncated)
+	assert.True(t, m.Children[0].MaxDepth)
+
+	_, err = Parse("#[]int [\n  0 => 1 #int\n")
+	assert.Equal(t, "godump: line 1: block is not closed", err.Error())
+}
+
+func TestParseMalformed(t *testing.T) {
+	_, err := Parse("#main.In {\n  +A => 1 #int\n  \n}\n")
+	assert.Equal(t, `godump: line 3: expected "label => value"`, err.Error())
+
+	_, err = Parse("#main.In {\n  +A => 1 #int\n  B => 2 #int\n}\n")
+	assert.Equal(t, `godump: line 3: expected "label => value"`, err.Error())
+}
+
+// FuzzParse checks that Parse returns an error for corrupted dumps instead of
+// panicking.
+func FuzzParse(f *testing.F) {
+	o := newParseOrder()
+	for _, d := range []*Dumper{
+		NewDumper(WithoutColor()),
+		NewDumper(WithoutColor(), WithCompact(60)),
+		NewDumper(WithoutColor(), WithMaxItems(1), WithMaxStringLen(3), WithMaxDepth(2)),
+		NewDumper(WithoutColor(), WithAddresses(), WithRedactFields("secret")),
+		NewDumper(WithoutColor(), WithChannelBuffers(), WithClosureCaptures()),
+	} {
+		f.Add(d.DumpStr(o, 42, "s", make(chan int, 1), func() {}, map[int]error{1: errors.New("x")}))
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		_, _ = Parse(text)
+	})
+}
+
+func TestParseConvertsToJSON(t *testing.T) {
+	text := "<#dump // main.go:3\n#main.User {\n  +Name => \"Alice\" #string\n  +Age  => 1_000 #int\n  -tags => #[]string [\"a\", \"b\"]\n}\n"
+	dumps, err := Parse(text)
+	assert.NoError(t, err)
+	assert.Equal(t, "main.go", dumps[0].File)
+	assert.JSONEq(t, `{"Name": "Alice", "Age": 1000, "tags": ["a", "b"]}`, DumpJSONStr(dumps[0].Values[0]))
+	assert.Contains(t, NewDumper().DumpHTML(dumps[0].Values[0]), "Alice")
+}
@@ -0,0 +1,107 @@
+package godump
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// typePlan caches what the dumper derives from a type alone, so values of the
+// same type do not repeat the reflection, string building and field matching.
+type typePlan struct {
+	// typeString is the rendered type name, as returned by getTypeString.
+	typeString string
+	// stringer reports whether values may be printed through fmt.Stringer.
+	// It is true for interface types, whose dynamic value decides.
+	stringer bool
+	// isError reports whether the type implements error.
+	isError bool
+	// hook is the registered type hook for the type, if any.
+	hook    typeHook
+	hasHook bool
+	// fields lists the struct fields that are printed, in declaration order,
+	// and labels their labels.
+	fields []fieldPlan
+	labels []string
+	// marshaler reports whether plain JSON defers to encoding/json for the
+	// type, and jsonFields lists the fields encoding/json would write.
+	marshaler  bool
+	jsonFields []jsonField
+}
+
+// fieldPlan holds the include, redact and tag decisions for one struct field.
+type fieldPlan struct {
+	index int
+	name  string
+	// label is the name the field is shown under.
+	label    string
+	exported bool
+	redact   bool
+	tag      fieldTag
+}
+
+// planCache maps a reflect.Type to its *typePlan. It is shared by a Dumper
+// and its clones, which all have the same field filters and type hooks.
+type planCache struct {
+	plans sync.Map
+}
+
+// plan returns the cached plan for t, building it on first use.
+func (d *Dumper) plan(t reflect.Type) *typePlan {
+	if d.plans == nil {
+		return d.buildPlan(t)
+	}
+	if p, ok := d.plans.plans.Load(t); ok {
+		return p.(*typePlan)
+	}
+	p, _ := d.plans.plans.LoadOrStore(t, d.buildPlan(t))
+	return p.(*typePlan)
+}
+
+// buildPlan derives the plan for t from the dumper configuration.
+func (d *Dumper) buildPlan(t reflect.Type) *typePlan {
+	p := &typePlan{
+		typeString: d.getTypeString(t),
+		stringer:   t.Kind() == reflect.Interface || t.Implements(stringerType),
+		isError:    t.Implements(errorType),
+	}
+	p.hook, p.hasHook = d.findTypeHook(t)
+	p.marshaler = isJSONMarshaler(t) || isJSONMarshaler(reflect.PtrTo(t))
+
+	if t.Kind() != reflect.Struct {
+		return p
+	}
+	p.jsonFields = jsonFieldsOf(t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := parseFieldTag(field)
+		if !d.includeField(field, tag) {
+			continue
+		}
+
+		f := fieldPlan{
+			index:    i,
+			name:     field.Name,
+			label:    tag.displayName(field),
+			exported: field.PkgPath == "",
+			redact:   d.redactField(field, tag),
+			tag:      tag,
+		}
+		p.fields = append(p.fields, f)
+		p.labels = append(p.labels, f.label)
+	}
+	return p
+}
+
+// field returns the plan of the struct field with the given index, if the
+// field is printed.
+func (p *typePlan) field(index int) (fieldPlan, bool) {
+	for _, f := range p.fields {
+		if f.index == index {
+			return f, true
+		}
+	}
+	return fieldPlan{}, false
+}
@@ -0,0 +1,128 @@
+package godump
+
+import (
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+type benchUser struct {
+	ID       int
+	Name     string
+	Email    string
+	Password string
+	Tags     []string
+	Profile  benchProfile
+	active   bool
+}
+
+type benchProfile struct {
+	Age     int
+	Country string
+	Score   float64
+}
+
+func benchUsers(n int) []benchUser {
+	users := make([]benchUser, n)
+	for i := range users {
+		users[i] = benchUser{
+			ID:       i,
+			Name:     "user",
+			Email:    "user@example.com",
+			Password: "secret",
+			Tags:     []string{"a", "b"},
+			Profile:  benchProfile{Age: 30, Country: "NL", Score: 1.5},
+			active:   i%2 == 0,
+		}
+	}
+	return users
+}
+
+func TestPlanIsCachedPerType(t *testing.T) {
+	d := NewDumper(WithRedactFields("Password"), WithExcludeFields("Email"))
+	typ := reflect.TypeOf(benchUser{})
+
+	p := d.plan(typ)
+	assert.True(t, p == d.plan(typ))
+	assert.True(t, p == d.clone().plan(typ))
+	assert.Equal(t, "godump.benchUser", p.typeString)
+	assert.False(t, p.stringer)
+
+	var names []string
+	for _, f := range p.fields {
+		names = append(names, f.label)
+		if f.name == "Password" {
+			assert.True(t, f.redact)
+		}
+	}
+	assert.Equal(t, []string{"ID", "Name", "Password", "Tags", "Profile", "active"}, names)
+}
+
+func TestPlanStringerCapability(t *testing.T) {
+	d := NewDumper()
+
+	assert.True(t, d.plan(reflect.TypeOf(FriendlyDuration(0))).stringer)
+	assert.True(t, d.plan(reflect.TypeOf((*any)(nil)).Elem()).stringer)
+	assert.False(t, d.plan(reflect.TypeOf(0)).stringer)
+	assert.True(t, d.plan(reflect.TypeOf((*error)(nil)).Elem()).isError)
+}
+
+func TestPlanCacheMatchesUncachedOutput(t *testing.T) {
+	users := benchUsers(3)
+
+	cached := newDumperT(t, WithoutHeader(), WithRedactSensitive())
+	uncached := newDumperT(t, WithoutHeader(), WithRedactSensitive())
+	uncached.plans = nil
+
+	assert.Equal(t, uncached.DumpStr(users), cached.DumpStr(users))
+}
+
+func TestPlanCacheConcurrentUse(t *testing.T) {
+	d := NewDumper(WithoutHeader(), WithoutColor())
+	users := benchUsers(2)
+	want := d.DumpStr(users)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, want, d.DumpStr(users))
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkDumpStructSlice(b *testing.B) {
+	users := benchUsers(1000)
+
+	b.Run("cached", func(b *testing.B) {
+		d := NewDumper(WithWriter(io.Discard), WithoutColor(), WithoutHeader(), WithMaxItems(len(users)), WithRedactSensitive())
+		benchmarkDump(b, d, users)
+	})
+	b.Run("uncached", func(b *testing.B) {
+		d := NewDumper(WithWriter(io.Discard), WithoutColor(), WithoutHeader(), WithMaxItems(len(users)), WithRedactSensitive())
+		d.plans = nil
+		benchmarkDump(b, d, users)
+	})
+}
+
+func BenchmarkDumpMap(b *testing.B) {
+	m := make(map[string]benchProfile, 1000)
+	for i := 0; i < 1000; i++ {
+		m[string(rune('a'+i%26))+string(rune('a'+i/26))] = benchProfile{Age: i}
+	}
+	d := NewDumper(WithWriter(io.Discard), WithoutColor(), WithoutHeader(), WithMaxItems(len(m)))
+	benchmarkDump(b, d, m)
+}
+
+func benchmarkDump(b *testing.B, d *Dumper, v any) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.Dump(v)
+	}
+}
@@ -0,0 +1,94 @@
+package godump
+
+import "reflect"
+
+// refKey identifies a value that can be reached more than once: a pointer, a
+// map, or a slice's backing array together with its length.
+type refKey struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+// refKeyOf returns the reference key for pointers, maps and non-empty slices.
+func refKeyOf(v reflect.Value) (refKey, bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map:
+		if v.IsNil() {
+			return refKey{}, false
+		}
+		return refKey{ptr: v.Pointer(), typ: v.Type()}, true
+	case reflect.Slice:
+		// Empty slices may all share the same zero-size allocation.
+		if v.IsNil() || v.Len() == 0 {
+			return refKey{}, false
+		}
+		return refKey{ptr: v.Pointer(), len: v.Len(), typ: v.Type()}, true
+	default:
+		return refKey{}, false
+	}
+}
+
+// trackRef records that the value behind key is being shown. When it was
+// shown before, it returns the id to refer back to and true; the id is 0 when
+// the pre-pass did not count the value, so it was shown without an anchor.
+// Otherwise it returns the id of the value's anchor, or 0 when it needs none.
+func trackRef(key refKey, state *dumpState) (int, bool) {
+	if id, seen := state.refs[key]; seen {
+		return id, true
+	}
+
+	id := 0
+	if state.shared[key] {
+		id = state.nextRefID
+		state.nextRefID++
+	}
+	state.refs[key] = id
+	return id, false
+}
+
+// countRefs walks v the way inspectValue does and records every reference that
+// is reached more than once, so only those get an anchor.
+func (d *Dumper) countRefs(v reflect.Value, indent int, state *dumpState, counts map[refKey]int) {
+	if !v.IsValid() || isNil(v) || shouldTruncateAtDepth(v, indent, d.maxDepth) || !state.budget.countable() {
+		return
+	}
+
+	if key, ok := refKeyOf(v); ok {
+		counts[key]++
+		if counts[key] > 1 {
+			state.shared[key] = true
+			return
+		}
+		d.recordRegion(v, key, state)
+	}
+
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		d.countRefs(v.Elem(), indent, state, counts)
+	case reflect.Struct:
+		for _, f := range d.plan(v.Type()).fields {
+			if !f.redact {
+				d.fieldDumper(f.tag, indent+1).countRefs(v.Field(f.index), indent+1, state, counts)
+			}
+		}
+	case reflect.Map:
+		for i, key := range d.sortedMapKeys(v) {
+			if i >= d.maxItems {
+				break
+			}
+			d.countRefs(v.MapIndex(key), indent+1, state, counts)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len() && i < d.maxItems; i++ {
+			d.countRefs(v.Index(i), indent+1, state, counts)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package godump
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+func TestRefAnchorOnFirstOccurrence(t *testing.T) {
+	type Node struct {
+		Next *Node
+	}
+	n := &Node{}
+	n.Next = n
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(n)
+	assert.Equal(t, "&1 #*godump.Node {\n  +Next => ↩︎ &1\n}\n", out)
+}
+
+func TestRefNoAnchorWithoutBackReference(t *testing.T) {
+	type Pair struct {
+		Left  *int
+		Right []string
+	}
+	n := 1
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(Pair{Left: &n, Right: []string{"a"}})
+	assert.NotContains(t, out, "&1")
+}
+
+func TestRefSelfReferencingMap(t *testing.T) {
+	m := map[string]any{"name": "root"}
+	m["self"] = m
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(m)
+	assert.Contains(t, out, "&1 #map[string]interface {} {")
+	assert.Contains(t, out, "self => ↩︎ &1")
+	assert.NotContains(t, out, "max depth")
+}
+
+func TestRefSharedSliceBackingArray(t *testing.T) {
+	type Buffers struct {
+		A    []int
+		B    []int
+		Head []int
+	}
+	s := []int{1, 2, 3}
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(Buffers{A: s, B: s, Head: s[:1]})
+	assert.Contains(t, out, "+A    => &1 #[]int [")
+	assert.Contains(t, out, "=> ↩︎ &1")
+	// Same backing array but a different length is a different value.
+	assert.Contains(t, out, "+Head => #[]int [")
+}
+
+func TestRefInterfacesHoldingPointers(t *testing.T) {
+	type Holder struct {
+		First  any
+		Second any
+	}
+	type Item struct {
+		ID int
+	}
+	item := &Item{ID: 7}
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(Holder{First: item, Second: item})
+	assert.Contains(t, out, "+First  => &1 #*godump.Item {")
+	assert.Contains(t, out, "+Second => ↩︎ &1")
+}
+
+func TestRefIDsSpanArguments(t *testing.T) {
+	v := &struct{ N int }{N: 1}
+
+	out := newDumperT(t, WithoutHeader()).DumpStr(v, v)
+	assert.Contains(t, out, "&1 #*struct { N int } {")
+	assert.Contains(t, out, "\n↩︎ &1\n")
+}
+
+func TestRefWithoutPrepassStillBreaksCycles(t *testing.T) {
+	m := map[string]any{}
+	m["self"] = m
+
+	d := newDumperT(t)
+	var sb strings.Builder
+	d.printValue(&sb, reflect.ValueOf(m), 0, newDumpState())
+	assert.Contains(t, sb.String(), "self => ↩︎ #map[string]interface {}")
+	assert.NotContains(t, sb.String(), "&1")
+}
+
+func TestRefWithoutPrePass(t *testing.T) {
+	type link struct {
+		Next *link
+	}
+	n := &link{}
+	n.Next = n
+
+	// Without the counting pass the first occurrence has no anchor, so the
+	// back-reference shows the type instead of an id.
+	d := newDumperT(t, WithoutHeader())
+	root := d.inspectValue(reflect.ValueOf(n), 0, newDumpState())
+	var sb strings.Builder
+	d.printNode(&sb, root, 0)
+	assert.Equal(t, "#*godump.link {\n  +Next => ↩︎ #*godump.link\n}", sb.String())
+
+	dumps, err := Parse(sb.String())
+	assert.NoError(t, err)
+	next := dumps[0].Values[0].Children[0]
+	assert.Equal(t, NodeRef, next.Kind)
+	assert.Equal(t, 0, next.Ref)
+	assert.Equal(t, "*godump.link", next.Type)
+
+	typed := newDumperT(t, WithJSONMode(JSONTyped))
+	var buf bytes.Buffer
+	assert.NoError(t, encodeJSON(&buf, typed.jsonNode(root), ""))
+	assert.Contains(t, buf.String(), `"Next":{"type":"*godump.link","cycle":true}`)
+}
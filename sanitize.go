@@ -0,0 +1,111 @@
+package godump
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithUnicodeEscapes prints bidi controls and invisible characters as \u
+// escapes instead of the default ‹U+XXXX› markers.
+// @group Options
+//
+// Example: escape invisible characters
+//
+//	// Default: ‹U+202E› markers
+//	d := godump.NewDumper(godump.WithUnicodeEscapes())
+//	d.Dump("admin\u202egnp.exe")
+//	// "admin\u202egnp.exe" #string
+func WithUnicodeEscapes() Option {
+	return func(d *Dumper) *Dumper {
+		d.unicodeEscapes = true
+		return d
+	}
+}
+
+// sanitize makes user-controlled text safe to write to a terminal or log.
+// Every string, map key, Stringer result and label goes through it.
+func (d *Dumper) sanitize(s string) string {
+	return sanitizeText(s, d.unicodeEscapes)
+}
+
+// escapeControl sanitizes s with the default markers.
+func escapeControl(s string) string {
+	return sanitizeText(s, false)
+}
+
+// namedEscapes holds the control characters with a familiar escape.
+var namedEscapes = map[rune]string{
+	'\n': `\n`,
+	'\t': `\t`,
+	'\r': `\r`,
+	'\v': `\v`,
+	'\f': `\f`,
+}
+
+// invisibleFillers are letters that render as blank space and are used to
+// disguise text, in addition to the Cf format characters.
+var invisibleFillers = map[rune]bool{
+	'\u115f': true, // Hangul choseong filler
+	'\u1160': true, // Hangul jungseong filler
+	'\u3164': true, // Hangul filler
+	'\uffa0': true, // Halfwidth Hangul filler
+}
+
+// sanitizeText escapes C0 and C1 controls, DEL, invalid UTF-8 and other
+// non-graphic characters, and marks bidi controls and invisible characters
+// such as zero-width spaces so they cannot reorder or hide text. The marks
+// are \u escapes when unicodeEscapes is set.
+func sanitizeText(s string, unicodeEscapes bool) string {
+	if isPlainASCII(s) {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&sb, `\x%02x`, s[i])
+		case r < 0x20 || r == 0x7f:
+			if esc, ok := namedEscapes[r]; ok {
+				sb.WriteString(esc)
+			} else {
+				fmt.Fprintf(&sb, `\x%02x`, r)
+			}
+		case unicode.Is(unicode.Cf, r) || invisibleFillers[r]:
+			if unicodeEscapes {
+				writeUnicodeEscape(&sb, r)
+			} else {
+				fmt.Fprintf(&sb, "‹U+%04X›", r)
+			}
+		case !unicode.IsGraphic(r):
+			writeUnicodeEscape(&sb, r)
+		default:
+			sb.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return sb.String()
+}
+
+// isPlainASCII reports whether s holds only printable ASCII.
+func isPlainASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// writeUnicodeEscape writes r as a Go \u or \U escape.
+func writeUnicodeEscape(sb *strings.Builder, r rune) {
+	if r <= 0xffff {
+		fmt.Fprintf(sb, `\u%04x`, r)
+		return
+	}
+	fmt.Fprintf(sb, `\U%08x`, r)
+}
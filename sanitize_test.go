@@ -0,0 +1,76 @@
+package godump
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+type hostileStringer struct{}
+
+func (hostileStringer) String() string { return "ok\x1b[2J\u202e" }
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name, in, want, escaped string
+	}{
+		{"plain", "hello, world", "hello, world", ""},
+		{"named controls", "a\nb\tc\rd\ve\ff", `a\nb\tc\rd\ve\ff`, ""},
+		{"other C0", "bell\a nul\x00 esc\x1b[31m", `bell\x07 nul\x00 esc\x1b[31m`, ""},
+		{"DEL", "del\x7f", `del\x7f`, ""},
+		{"C1", "csi\u009b2J nel\u0085", `csi\u009b2J nel\u0085`, ""},
+		{"invalid UTF-8", "bad\xff\xfe", `bad\xff\xfe`, ""},
+		{"line separators", "a\u2028b\u2029c", `a\u2028b\u2029c`, ""},
+		{"bidi override", "admin\u202egnp.exe", "admin‹U+202E›gnp.exe", `admin\u202egnp.exe`},
+		{"bidi isolates", "\u2066x\u2069", "‹U+2066›x‹U+2069›", `\u2066x\u2069`},
+		{"zero width", "pay\u200bpal\ufeff", "pay‹U+200B›pal‹U+FEFF›", `pay\u200bpal\ufeff`},
+		{"filler", "id\u3164", "id‹U+3164›", `id\u3164`},
+		{"tag character", "x\U000e0041", "x‹U+E0041›", `x\U000e0041`},
+		{"printable unicode", "héllo 世界 🙂 a\u00a0b", "héllo 世界 🙂 a\u00a0b", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitizeText(tt.in, false))
+			escaped := tt.escaped
+			if escaped == "" {
+				escaped = tt.want
+			}
+			assert.Equal(t, escaped, sanitizeText(tt.in, true))
+		})
+	}
+}
+
+func TestSanitizeEveryTextFragment(t *testing.T) {
+	type Labeled struct {
+		Name string "godump:\"name=na\u200bme\""
+	}
+	d := newDumperT(t, WithoutHeader(), WithTypeExpander(reflect.TypeOf(Labeled{}), func(v reflect.Value) []Child {
+		return []Child{{Label: "lbl\x1b", Value: 1}}
+	}))
+
+	out := d.DumpStr(map[string]int{"k\x1b]0;pwned\a": 1})
+	assert.Contains(t, out, `k\x1b]0;pwned\x07 => 1`)
+
+	out = d.DumpStr(hostileStringer{})
+	assert.Contains(t, out, `ok\x1b[2J‹U+202E› #godump.hostileStringer`)
+
+	out = d.DumpStr(Labeled{})
+	assert.Contains(t, out, `lbl\x1b => 1`)
+
+	out = newDumperT(t, WithoutHeader()).DumpStr(Labeled{Name: "\u009b"})
+	assert.Contains(t, out, `+na‹U+200B›me => "\u009b"`)
+
+	out = newDumperT(t, WithoutHeader(), WithCompact(80)).DumpStr(map[string]string{"\x1b": "\u202e"})
+	assert.Equal(t, "#map[string]string {\\x1b: \"‹U+202E›\"}\n", out)
+
+	out = newDumperT(t, WithoutHeader(), WithUnicodeEscapes()).DumpStr("a\u202eb")
+	assert.Equal(t, "\"a\\u202eb\" #string\n", out)
+
+	for _, s := range []string{out, d.DumpStr(hostileStringer{}), d.DumpStr(map[string]int{"\x1b\u0085\x7f": 1})} {
+		if strings.ContainsAny(s, "\x1b\u0085\x7f\u202e") {
+			t.Errorf("raw control character in %q", s)
+		}
+	}
+}
@@ -0,0 +1,439 @@
+package godump
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Role is the meaning of a piece of rendered text, such as a type name or a
+// map key. Themes map roles to styles, and class-based HTML marks text with
+// a CSS class named gd-<role>.
+type Role string
+
+// Semantic roles of rendered text.
+const (
+	RoleType       Role = "type"         // #type annotations and type names
+	RoleField      Role = "field"        // struct field names
+	RoleVisibility Role = "visibility"   // the +/- markers of exported and unexported fields
+	RoleKey        Role = "key"          // map keys and block labels
+	RoleIndex      Role = "index"        // list and channel indexes
+	RoleString     Role = "string"       // string contents and printable bytes
+	RoleQuote      Role = "quote"        // quotes around strings
+	RoleNumber     Role = "number"       // numbers, hex bytes and pointers
+	RoleBool       Role = "bool"         // true and false
+	RoleNil        Role = "nil"          // the type of nil values
+	RoleText       Role = "text"         // Stringer and formatter text, function names, byte dump frames
+	RoleRef        Role = "ref"          // &N anchors, ↩︎ &N references and addresses
+	RoleRedacted   Role = "redacted"     // <redacted> placeholders
+	RoleWarn       Role = "warn"         // aliasing and full or closed channels
+	RoleMeta       Role = "meta"         // channel details, hex offsets and source locations
+	RoleMuted      Role = "muted"        // (nil), truncation and depth markers, hex dump rules
+	RoleHeader     Role = "header"       // dump and diff headers
+	RoleDiffDel    Role = "diff-del"     // background of lines removed in a diff
+	RoleDiffAdd    Role = "diff-add"     // background of lines added in a diff
+	RoleDiffDelSym Role = "diff-del-sym" // the - marker of removed lines
+	RoleDiffAddSym Role = "diff-add-sym" // the + marker of added lines
+)
+
+// roles lists every role, in stylesheet order.
+var roles = []Role{
+	RoleType, RoleField, RoleVisibility, RoleKey, RoleIndex, RoleString, RoleQuote,
+	RoleNumber, RoleBool, RoleNil, RoleText, RoleRef, RoleRedacted, RoleWarn, RoleMeta,
+	RoleMuted, RoleHeader, RoleDiffDel, RoleDiffAdd, RoleDiffDelSym, RoleDiffAddSym,
+}
+
+// isRole reports whether code names a role rather than a raw color code.
+func isRole(code string) bool {
+	for _, role := range roles {
+		if string(role) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isBackgroundRole reports whether role styles the background of a line.
+func isBackgroundRole(role Role) bool {
+	return role == RoleDiffDel || role == RoleDiffAdd
+}
+
+// Style is how a theme draws text of one role.
+type Style struct {
+	// ANSI is the escape sequence that starts the style in a terminal, such
+	// as "\033[1;32m". Empty leaves the text unstyled.
+	ANSI string
+	// Color is the CSS color of the text in HTML, or of the line background
+	// for RoleDiffDel and RoleDiffAdd. Empty leaves the color unchanged.
+	Color string
+	// Bold renders the text in bold in HTML.
+	Bold bool
+}
+
+// Theme maps the roles of rendered text to styles. The terminal, HTML and
+// diff renderers all draw from the dumper's theme; roles missing from Styles
+// are left unstyled.
+type Theme struct {
+	// Background and Foreground are the CSS colors of HTML dump blocks.
+	Background string
+	Foreground string
+	// Styles holds the style of each role.
+	Styles map[Role]Style
+}
+
+// DarkTheme returns the default theme, for dark terminals and pages. Each
+// call returns a new copy, so changing its Styles affects no other dumper.
+// @group Themes
+//
+// Example: dim type annotations in the default theme
+//
+//	theme := godump.DarkTheme()
+//	theme.Styles[godump.RoleType] = godump.Style{ANSI: "\033[2m", Color: "#666"}
+//	d := godump.NewDumper(godump.WithTheme(theme))
+//	d.Dump(1)
+//	// 1 #int
+func DarkTheme() Theme {
+	return darkTheme.clone()
+}
+
+// LightTheme returns a theme with darker colors that stay readable on light
+// backgrounds.
+// @group Themes
+//
+// Example: readable output on a light terminal
+//
+//	d := godump.NewDumper(godump.WithTheme(godump.LightTheme()))
+//	d.Dump("hi")
+//	// "hi" #string
+func LightTheme() Theme {
+	return lightTheme.clone()
+}
+
+// HighContrastTheme returns a theme with bright, bold colors on black.
+// @group Themes
+//
+// Example: bold colors for presentations
+//
+//	d := godump.NewDumper(godump.WithTheme(godump.HighContrastTheme()))
+//	d.Dump(true)
+//	// true #bool
+func HighContrastTheme() Theme {
+	return highContrastTheme.clone()
+}
+
+// MonochromeTheme returns a theme with no colors, only bold, dim and
+// underlined text, for terminals and pages where colors are unavailable or
+// unwanted.
+// @group Themes
+//
+// Example: bold keys without colors
+//
+//	d := godump.NewDumper(godump.WithTheme(godump.MonochromeTheme()))
+//	d.Dump(map[string]int{"a": 1})
+//	// #map[string]int {
+//	//   a => 1 #int
+//	// }
+func MonochromeTheme() Theme {
+	return monochromeTheme.clone()
+}
+
+// clone returns a copy of the theme with its own Styles map.
+func (t Theme) clone() Theme {
+	styles := make(map[Role]Style, len(t.Styles))
+	for role, s := range t.Styles {
+		styles[role] = s
+	}
+	t.Styles = styles
+	return t
+}
+
+// darkTheme is the theme returned by DarkTheme, which dumpers draw with when
+// no theme is set.
+var darkTheme = Theme{
+	Background: "black",
+	Foreground: "white",
+	Styles: map[Role]Style{
+		RoleType:       {ANSI: colorGray, Color: "#999"},
+		RoleVisibility: {ANSI: colorYellow, Color: "#ffb400"},
+		RoleKey:        {ANSI: colorMeta, Color: "#d087d0"},
+		RoleIndex:      {ANSI: colorCyan, Color: "#40c0ff"},
+		RoleString:     {ANSI: colorLime, Color: "#80ff80"},
+		RoleQuote:      {ANSI: colorYellow, Color: "#ffb400"},
+		RoleNumber:     {ANSI: colorCyan, Color: "#40c0ff"},
+		RoleBool:       {ANSI: colorYellow, Color: "#ffb400"},
+		RoleNil:        {ANSI: colorLime, Color: "#80ff80"},
+		RoleText:       {ANSI: colorLime, Color: "#80ff80"},
+		RoleRef:        {ANSI: colorRef, Color: "#aaa"},
+		RoleRedacted:   {ANSI: colorRed, Color: "#ff5f5f"},
+		RoleWarn:       {ANSI: colorRed, Color: "#ff5f5f"},
+		RoleMeta:       {ANSI: colorMeta, Color: "#d087d0"},
+		RoleMuted:      {ANSI: colorGray, Color: "#999"},
+		RoleHeader:     {ANSI: colorGray, Color: "#999"},
+		RoleDiffDel:    {ANSI: colorRedBg, Color: "#221010"},
+		RoleDiffAdd:    {ANSI: colorGreenBg, Color: "#102216"},
+		RoleDiffDelSym: {ANSI: colorRed, Color: "#ff5f5f"},
+		RoleDiffAddSym: {ANSI: colorGreen, Color: "#55d655"},
+	},
+}
+
+// lightTheme is the theme returned by LightTheme.
+var lightTheme = Theme{
+	Background: "#ffffff",
+	Foreground: "#1f2328",
+	Styles: map[Role]Style{
+		RoleType:       {ANSI: "\033[38;5;244m", Color: "#6e7781"},
+		RoleVisibility: {ANSI: "\033[38;5;130m", Color: "#953800"},
+		RoleKey:        {ANSI: "\033[38;5;90m", Color: "#8250df"},
+		RoleIndex:      {ANSI: "\033[38;5;25m", Color: "#0550ae"},
+		RoleString:     {ANSI: "\033[38;5;28m", Color: "#116329"},
+		RoleQuote:      {ANSI: "\033[38;5;130m", Color: "#953800"},
+		RoleNumber:     {ANSI: "\033[38;5;25m", Color: "#0550ae"},
+		RoleBool:       {ANSI: "\033[38;5;130m", Color: "#953800"},
+		RoleNil:        {ANSI: "\033[38;5;28m", Color: "#116329"},
+		RoleText:       {ANSI: "\033[38;5;28m", Color: "#116329"},
+		RoleRef:        {ANSI: "\033[38;5;240m", Color: "#57606a"},
+		RoleRedacted:   {ANSI: "\033[38;5;160m", Color: "#cf222e"},
+		RoleWarn:       {ANSI: "\033[38;5;160m", Color: "#cf222e"},
+		RoleMeta:       {ANSI: "\033[38;5;90m", Color: "#8250df"},
+		RoleMuted:      {ANSI: "\033[38;5;244m", Color: "#6e7781"},
+		RoleHeader:     {ANSI: "\033[38;5;244m", Color: "#6e7781"},
+		RoleDiffDel:    {ANSI: "\033[48;2;255;235;233m", Color: "#ffebe9"},
+		RoleDiffAdd:    {ANSI: "\033[48;2;230;255;236m", Color: "#e6ffec"},
+		RoleDiffDelSym: {ANSI: "\033[38;5;160m", Color: "#cf222e"},
+		RoleDiffAddSym: {ANSI: "\033[38;5;28m", Color: "#116329"},
+	},
+}
+
+// highContrastTheme is the theme returned by HighContrastTheme.
+var highContrastTheme = Theme{
+	Background: "black",
+	Foreground: "white",
+	Styles: map[Role]Style{
+		RoleType:       {ANSI: "\033[37m", Color: "#d0d0d0"},
+		RoleField:      {ANSI: "\033[1;97m", Color: "#ffffff", Bold: true},
+		RoleVisibility: {ANSI: "\033[1;93m", Color: "#ffff00", Bold: true},
+		RoleKey:        {ANSI: "\033[1;95m", Color: "#ff80ff", Bold: true},
+		RoleIndex:      {ANSI: "\033[1;96m", Color: "#00ffff", Bold: true},
+		RoleString:     {ANSI: "\033[1;92m", Color: "#00ff00", Bold: true},
+		RoleQuote:      {ANSI: "\033[1;93m", Color: "#ffff00", Bold: true},
+		RoleNumber:     {ANSI: "\033[1;96m", Color: "#00ffff", Bold: true},
+		RoleBool:       {ANSI: "\033[1;93m", Color: "#ffff00", Bold: true},
+		RoleNil:        {ANSI: "\033[1;92m", Color: "#00ff00", Bold: true},
+		RoleText:       {ANSI: "\033[1;92m", Color: "#00ff00", Bold: true},
+		RoleRef:        {ANSI: "\033[1;97m", Color: "#ffffff", Bold: true},
+		RoleRedacted:   {ANSI: "\033[1;91m", Color: "#ff4040", Bold: true},
+		RoleWarn:       {ANSI: "\033[1;91m", Color: "#ff4040", Bold: true},
+		RoleMeta:       {ANSI: "\033[1;95m", Color: "#ff80ff", Bold: true},
+		RoleMuted:      {ANSI: "\033[37m", Color: "#d0d0d0"},
+		RoleHeader:     {ANSI: "\033[37m", Color: "#d0d0d0"},
+		RoleDiffDel:    {ANSI: "\033[48;5;52m", Color: "#5f0000"},
+		RoleDiffAdd:    {ANSI: "\033[48;5;22m", Color: "#005f00"},
+		RoleDiffDelSym: {ANSI: "\033[1;91m", Color: "#ff4040", Bold: true},
+		RoleDiffAddSym: {ANSI: "\033[1;92m", Color: "#00ff00", Bold: true},
+	},
+}
+
+// monochromeTheme is the theme returned by MonochromeTheme.
+var monochromeTheme = Theme{
+	Background: "white",
+	Foreground: "black",
+	Styles: map[Role]Style{
+		RoleType:       {ANSI: "\033[2m", Color: "#777"},
+		RoleVisibility: {ANSI: "\033[1m", Bold: true},
+		RoleKey:        {ANSI: "\033[1m", Bold: true},
+		RoleIndex:      {ANSI: "\033[1m", Bold: true},
+		RoleRef:        {ANSI: "\033[4m", Color: "#555"},
+		RoleRedacted:   {ANSI: "\033[1m", Bold: true},
+		RoleWarn:       {ANSI: "\033[1m", Bold: true},
+		RoleMuted:      {ANSI: "\033[2m", Color: "#777"},
+		RoleHeader:     {ANSI: "\033[2m", Color: "#777"},
+		RoleDiffDel:    {ANSI: "\033[2m", Color: "#e8e8e8"},
+		RoleDiffAdd:    {ANSI: "\033[1m", Color: "#f8f8f8"},
+		RoleDiffDelSym: {ANSI: "\033[1m", Bold: true},
+		RoleDiffAddSym: {ANSI: "\033[1m", Bold: true},
+	},
+}
+
+// WithTheme sets the colors of terminal, HTML and diff output. The default is
+// DarkTheme; LightTheme, HighContrastTheme and MonochromeTheme are built in,
+// and any other Theme can be passed. The dumper keeps a copy of t, so later
+// changes to its Styles do not affect it.
+// @group Options
+//
+// Example: readable output on a light terminal
+//
+//	d := godump.NewDumper(godump.WithTheme(godump.LightTheme()))
+//	d.Dump(map[string]int{"a": 1})
+//	// #map[string]int {
+//	//   a => 1 #int
+//	// }
+func WithTheme(t Theme) Option {
+	return func(d *Dumper) *Dumper {
+		t = t.clone()
+		d.theme = &t
+		return d
+	}
+}
+
+// WithHTMLTheme makes DumpHTML, DiffHTML and DumpHTMLPage mark text with
+// semantic CSS classes such as gd-type, gd-key, gd-string, gd-ref,
+// gd-redacted and gd-diff-del instead of inline colors, inside a
+// <pre class="godump"> block. stylesheet is included in a <style> element
+// before the output; pass HTMLStylesheet() for the default look, or an empty
+// string when the page styles the classes itself.
+// @group Options
+//
+// Example: style HTML dumps from your own stylesheet
+//
+//	d := godump.NewDumper(godump.WithoutHeader(), godump.WithHTMLTheme(""))
+//	html := d.DumpHTML(map[string]int{"a": 1})
+//	fmt.Println(html)
+//	// <pre class="godump">
+//	// <span class="gd-type">#map[string]int</span> {
+//	//    <span class="gd-key">a</span> => <span class="gd-number">1</span><span class="gd-type"> #int</span>
+//	// }
+//	// </pre>
+func WithHTMLTheme(stylesheet string) Option {
+	return func(d *Dumper) *Dumper {
+		d.htmlClasses = true
+		d.htmlStylesheet = stylesheet
+		return d
+	}
+}
+
+// HTMLStylesheet returns the stylesheet of DarkTheme for the classes emitted
+// with WithHTMLTheme. Colors are CSS custom properties on the .godump block,
+// such as --gd-string, so a light theme or dark mode toggle only needs to
+// override them.
+// @group HTML
+//
+// Example: override colors for a light page
+//
+//	css := godump.HTMLStylesheet() + `
+//	.godump { --gd-bg: #fff; --gd-fg: #222; --gd-string: #1a7f37; }`
+//	d := godump.NewDumper(godump.WithHTMLTheme(css))
+//	_ = d.DumpHTML(map[string]int{"a": 1})
+func HTMLStylesheet() string {
+	return darkTheme.Stylesheet()
+}
+
+// Stylesheet returns the theme as a stylesheet for the classes emitted with
+// WithHTMLTheme.
+// @group HTML
+//
+// Example: style class-based HTML with the light theme
+//
+//	d := godump.NewDumper(godump.WithHTMLTheme(godump.LightTheme().Stylesheet()))
+//	_ = d.DumpHTML(map[string]int{"a": 1})
+func (t Theme) Stylesheet() string {
+	var sb strings.Builder
+	sb.WriteString(".godump {\n  --gd-bg: " + t.Background + ";\n  --gd-fg: " + t.Foreground + ";\n")
+	for _, role := range roles {
+		if c := t.Styles[role].Color; c != "" {
+			sb.WriteString("  --gd-" + string(role) + ": " + c + ";\n")
+		}
+	}
+	sb.WriteString("}\n")
+	sb.WriteString("pre.godump { background-color: var(--gd-bg); color: var(--gd-fg); padding: 5px; border-radius: 5px; }\n")
+	for _, role := range roles {
+		s := t.Styles[role]
+		var decls []string
+		switch {
+		case s.Color != "" && isBackgroundRole(role):
+			decls = append(decls, "display: block; width: 100%; background-color: var(--gd-"+string(role)+");")
+		case s.Color != "":
+			decls = append(decls, "color: var(--gd-"+string(role)+");")
+		}
+		if s.Bold {
+			decls = append(decls, "font-weight: bold;")
+		}
+		if len(decls) > 0 {
+			sb.WriteString(".godump .gd-" + string(role) + " { " + strings.Join(decls, " ") + " }\n")
+		}
+	}
+	return sb.String()
+}
+
+// colorizeANSI colorizes the string using ANSI escape codes. code is a role,
+// which is looked up in the theme, or an escape code.
+//
+// It satisfies the [Colorizer] interface.
+func (t *Theme) colorizeANSI(code, str string) string {
+	if isRole(code) {
+		code = t.Styles[Role(code)].ANSI
+	}
+	if code == colorPlain {
+		return str
+	}
+	return code + str + colorReset
+}
+
+// colorizeHTML HTML-escapes the string and colorizes it using span tags with
+// the style of its role in the theme.
+//
+// It satisfies the [Colorizer] interface.
+func (t *Theme) colorizeHTML(code, str string) string {
+	// Raw ANSI codes have no HTML color.
+	if !isRole(code) {
+		return html.EscapeString(str)
+	}
+	s := t.Styles[Role(code)]
+	switch {
+	case s.Color != "" && s.Bold:
+		return fmt.Sprintf(`<span style="color:%s; font-weight:bold">%s</span>`, s.Color, html.EscapeString(str))
+	case s.Color != "":
+		return fmt.Sprintf(`<span style="color:%s">%s</span>`, s.Color, html.EscapeString(str))
+	case s.Bold:
+		return `<span style="font-weight:bold">` + html.EscapeString(str) + `</span>`
+	}
+	return html.EscapeString(str)
+}
+
+// colorizeHTMLClasses HTML-escapes the string and wraps it in a span with the
+// CSS class of its role.
+//
+// It satisfies the [Colorizer] interface.
+func colorizeHTMLClasses(code, str string) string {
+	if !isRole(code) {
+		return html.EscapeString(str)
+	}
+	return `<span class="gd-` + code + `">` + html.EscapeString(str) + `</span>`
+}
+
+// currentTheme returns the theme the dumper draws with.
+func (d *Dumper) currentTheme() *Theme {
+	if d.theme == nil {
+		return &darkTheme
+	}
+	return d.theme
+}
+
+// htmlOpen returns the markup that starts an HTML dump or diff block.
+func (d *Dumper) htmlOpen() string {
+	if !d.htmlClasses {
+		t := d.currentTheme()
+		return `<div style='background-color:` + t.Background + `;'><pre style="background-color:` + t.Background + `; color:` + t.Foreground + `; padding:5px; border-radius: 5px">` + "\n"
+	}
+	style := ""
+	if d.htmlStylesheet != "" {
+		style = "<style>\n" + d.htmlStylesheet + "</style>\n"
+	}
+	return style + `<pre class="godump">` + "\n"
+}
+
+// htmlClose returns the markup that ends an HTML dump or diff block.
+func (d *Dumper) htmlClose() string {
+	if !d.htmlClasses {
+		return "</pre></div>"
+	}
+	return "</pre>"
+}
+
+// htmlClone returns a copy of the dumper that renders HTML.
+func (d *Dumper) htmlClone() *Dumper {
+	h := d.clone()
+	h.htmlOutput = true
+	h.colorDepth = colorDepthTrue
+	h.colorizer = d.htmlColorizer()
+	return h
+}
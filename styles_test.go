@@ -0,0 +1,122 @@
+package godump
+
+import (
+	"strings"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+func TestWithHTMLTheme(t *testing.T) {
+	d := NewDumper(WithoutHeader(), WithHTMLTheme(HTMLStylesheet()))
+	out := d.DumpHTML(map[string]any{"name": "Alice", "ok": true, "n": nil})
+
+	assert.True(t, strings.HasPrefix(out, "<style>\n.godump {"))
+	assert.Contains(t, out, `<pre class="godump">`)
+	assert.True(t, strings.HasSuffix(out, "</pre>"))
+	assert.Contains(t, out, `<span class="gd-type">#map[string]interface {}</span>`)
+	assert.Contains(t, out, `<span class="gd-key">name</span>`)
+	assert.Contains(t, out, `<span class="gd-string">Alice</span>`)
+	assert.Contains(t, out, `<span class="gd-bool">true</span>`)
+	assert.NotContains(t, out, "style=")
+
+	// Without a stylesheet only the classes are emitted.
+	out = NewDumper(WithoutHeader(), WithHTMLTheme("")).DumpHTML(1)
+	assert.Equal(t, "<pre class=\"godump\">\n<span class=\"gd-number\">1</span><span class=\"gd-type\"> #int</span>\n</pre>", out)
+
+	// WithoutColor still escapes text and emits no classes.
+	out = NewDumper(WithoutHeader(), WithoutColor(), WithHTMLTheme("")).DumpHTML("<b>")
+	assert.Equal(t, "<pre class=\"godump\">\n&#34;&lt;b&gt;&#34; #string\n</pre>", out)
+}
+
+func TestDiffHTMLClasses(t *testing.T) {
+	d := NewDumper(WithHTMLTheme(""))
+	out := d.DiffHTML(map[string]int{"a": 1}, map[string]int{"a": 2})
+
+	assert.Contains(t, out, `<pre class="godump">`)
+	assert.Contains(t, out, `<span class="gd-diff-del-sym">-</span> <span class="gd-diff-del">   <span class="gd-key">a</span>`)
+	assert.Contains(t, out, `<span class="gd-diff-add-sym">+</span> <span class="gd-diff-add">   <span class="gd-key">a</span>`)
+	assert.Contains(t, out, `<span class="gd-header">`)
+	assert.NotContains(t, out, "style=")
+	assert.NotContains(t, out, string(ansiEscape))
+}
+
+func TestHTMLStylesheet(t *testing.T) {
+	css := HTMLStylesheet()
+	for _, role := range roles {
+		c := DarkTheme().Styles[role].Color
+		if c == "" {
+			continue
+		}
+		assert.Contains(t, css, "--gd-"+string(role)+": "+c+";")
+		assert.Contains(t, css, ".godump .gd-"+string(role)+" {")
+	}
+	assert.Contains(t, css, ".godump .gd-string { color: var(--gd-string); }")
+	assert.Contains(t, css, ".godump .gd-diff-add { display: block; width: 100%; background-color: var(--gd-diff-add); }")
+	assert.NotContains(t, css, "<")
+
+	css = LightTheme().Stylesheet()
+	assert.Contains(t, css, "--gd-bg: #ffffff;")
+	assert.Contains(t, css, "--gd-string: #116329;")
+
+	css = MonochromeTheme().Stylesheet()
+	assert.Contains(t, css, ".godump .gd-key { font-weight: bold; }")
+	assert.NotContains(t, css, ".gd-string")
+}
+
+func TestWithTheme(t *testing.T) {
+	d := NewDumper(WithoutHeader(), WithTheme(LightTheme()))
+	unsetColorEnv(t)
+	t.Setenv("FORCE_COLOR", "3")
+	out := d.DumpStr("hi")
+	assert.Equal(t, LightTheme().Styles[RoleQuote].ANSI+`"`+colorReset+
+		LightTheme().Styles[RoleString].ANSI+"hi"+colorReset+
+		LightTheme().Styles[RoleQuote].ANSI+`"`+colorReset+
+		LightTheme().Styles[RoleType].ANSI+" #string"+colorReset, strings.TrimSuffix(out, "\n"))
+
+	html := d.DumpHTML("hi")
+	assert.Contains(t, html, `<pre style="background-color:#ffffff; color:#1f2328;`)
+	assert.Contains(t, html, `<span style="color:#116329">hi</span>`)
+
+	// Bold styles without a color are drawn in bold only.
+	html = NewDumper(WithoutHeader(), WithTheme(MonochromeTheme())).DumpHTML(map[string]int{"a": 1})
+	assert.Contains(t, html, `<span style="font-weight:bold">a</span> => 1`)
+	assert.Equal(t, "#map[string]int {\n   a => 1 #int\n}\n", stripHTMLSpans(html[strings.Index(html, "#map"):strings.Index(html, "</pre>")]))
+
+	// Diff lines take their backgrounds from the theme.
+	contrast := HighContrastTheme()
+	d = NewDumper(WithTheme(contrast))
+	d.colorizer = contrast.colorizeANSI
+	diff := d.DiffStr(1, 2)
+	assert.Contains(t, diff, HighContrastTheme().Styles[RoleDiffDel].ANSI)
+	assert.Contains(t, diff, HighContrastTheme().Styles[RoleDiffAddSym].ANSI+"+")
+	assert.NotContains(t, diff, colorRedBg)
+	assert.Contains(t, NewDumper(WithTheme(HighContrastTheme())).DiffHTML(1, 2), "background-color:#5f0000;")
+}
+
+func TestThemesAreCopies(t *testing.T) {
+	theme := DarkTheme()
+	theme.Styles[RoleString] = Style{Color: "red"}
+	assert.Equal(t, "#80ff80", DarkTheme().Styles[RoleString].Color)
+	assert.Contains(t, HTMLStylesheet(), "--gd-string: #80ff80;")
+
+	// Changing a theme after passing it to WithTheme leaves the dumper as is.
+	d := NewDumper(WithoutHeader(), WithTheme(theme))
+	theme.Styles[RoleString] = Style{Color: "blue"}
+	assert.Contains(t, d.DumpHTML("hi"), `<span style="color:red">hi</span>`)
+	assert.Contains(t, NewDumper(WithoutHeader()).DumpHTML("hi"), `<span style="color:#80ff80">hi</span>`)
+}
+
+func TestBuiltinThemesCoverRoles(t *testing.T) {
+	for name, theme := range map[string]Theme{"dark": DarkTheme(), "light": LightTheme(), "contrast": HighContrastTheme()} {
+		for _, role := range roles {
+			if role == RoleField {
+				continue
+			}
+			s := theme.Styles[role]
+			if s.ANSI == "" || s.Color == "" {
+				t.Errorf("%s theme has no style for %s", name, role)
+			}
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package godump
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// colorDepth is the number of colors a terminal can display. The zero value
+// leaves colors as the theme defines them.
+type colorDepth int
+
+const (
+	colorDepthTrue colorDepth = iota // 24-bit color
+	colorDepth256                    // the xterm 256-color palette
+	colorDepth16                     // the 8 basic colors and their bright variants
+	colorDepthNone                   // no color
+)
+
+// detectColorDepth returns the colors to use for output to w. Colors are off
+// unless w is a terminal, or when NO_COLOR is set. FORCE_COLOR turns them on
+// for any writer, with at least 16 colors, or 256 and 24-bit colors for the
+// values 2 and 3; FORCE_COLOR=0 turns them off. TERM and COLORTERM decide
+// the depth.
+func detectColorDepth(w io.Writer) colorDepth {
+	if os.Getenv("NO_COLOR") != "" {
+		return colorDepthNone
+	}
+	depth := termColorDepth(os.Getenv("TERM"), os.Getenv("COLORTERM"))
+	if force, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		forced := colorDepth16
+		switch strings.ToLower(force) {
+		case "0", "false", "no", "off":
+			return colorDepthNone
+		case "2":
+			forced = colorDepth256
+		case "3":
+			forced = colorDepthTrue
+		}
+		if depth > forced {
+			depth = forced
+		}
+		return depth
+	}
+	if !isTerminal(w) {
+		return colorDepthNone
+	}
+	return depth
+}
+
+// termColorDepth returns the colors a terminal supports from the values of
+// TERM and COLORTERM.
+func termColorDepth(term, colorterm string) colorDepth {
+	switch {
+	case colorterm == "truecolor" || colorterm == "24bit":
+		return colorDepthTrue
+	case term == "dumb":
+		return colorDepthNone
+	case strings.Contains(term, "truecolor") || strings.Contains(term, "24bit") || strings.Contains(term, "direct"):
+		return colorDepthTrue
+	case strings.Contains(term, "256"):
+		return colorDepth256
+	default:
+		return colorDepth16
+	}
+}
+
+// isTerminal reports whether w writes to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && f != nil && isTerminalFile(f)
+}
+
+// ansiColorizer returns a colorizer drawing the theme with the colors of a
+// terminal of the given depth.
+func (t *Theme) ansiColorizer(depth colorDepth) Colorizer {
+	switch depth {
+	case colorDepthNone:
+		return colorizeUnstyled
+	case colorDepthTrue:
+		return t.colorizeANSI
+	}
+
+	reduced := &Theme{Styles: make(map[Role]Style, len(t.Styles))}
+	for role, s := range t.Styles {
+		s.ANSI = downsampleANSI(s.ANSI, depth)
+		reduced.Styles[role] = s
+	}
+	return func(code, str string) string {
+		if !isRole(code) {
+			code = downsampleANSI(code, depth)
+		}
+		return reduced.colorizeANSI(code, str)
+	}
+}
+
+// downsampleANSI rewrites the 256-color and 24-bit colors of an SGR escape
+// sequence to the nearest colors a terminal of the given depth displays.
+// Other sequences and parameters are returned unchanged.
+func downsampleANSI(code string, depth colorDepth) string {
+	if depth == colorDepthTrue || depth == colorDepthNone ||
+		!strings.HasPrefix(code, "\033[") || !strings.HasSuffix(code, "m") {
+		return code
+	}
+
+	params := strings.Split(code[2:len(code)-1], ";")
+	out := make([]string, 0, len(params))
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		if (p != "38" && p != "48") || i+2 >= len(params) {
+			out = append(out, p)
+			continue
+		}
+		bg := p == "48"
+		switch params[i+1] {
+		case "5":
+			n, err := strconv.Atoi(params[i+2])
+			if err != nil || n < 0 || n > 255 {
+				out = append(out, p)
+				continue
+			}
+			if depth == colorDepth256 {
+				out = append(out, p, "5", params[i+2])
+			} else {
+				r, g, b := xterm256RGB(n)
+				out = append(out, ansi16(r, g, b, bg))
+			}
+			i += 2
+		case "2":
+			if i+4 >= len(params) {
+				out = append(out, p)
+				continue
+			}
+			r, errR := strconv.Atoi(params[i+2])
+			g, errG := strconv.Atoi(params[i+3])
+			b, errB := strconv.Atoi(params[i+4])
+			if errR != nil || errG != nil || errB != nil {
+				out = append(out, p)
+				continue
+			}
+			if depth == colorDepth256 {
+				out = append(out, p, "5", strconv.Itoa(xterm256Index(r, g, b)))
+			} else {
+				out = append(out, ansi16(r, g, b, bg))
+			}
+			i += 4
+		default:
+			out = append(out, p)
+		}
+	}
+	return "\033[" + strings.Join(out, ";") + "m"
+}
+
+// basic16RGB holds the xterm colors of the 16 basic palette entries.
+var basic16RGB = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube of the xterm
+// 256-color palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// xterm256RGB returns the color of an xterm 256-color palette entry.
+func xterm256RGB(n int) (r, g, b int) {
+	switch {
+	case n < 16:
+		c := basic16RGB[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	default:
+		v := 8 + (n-232)*10
+		return v, v, v
+	}
+}
+
+// xterm256Index returns the xterm 256-color palette entry nearest to a
+// 24-bit color, from the color cube or the gray ramp.
+func xterm256Index(r, g, b int) int {
+	level := func(v int) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	cr, cg, cb := level(r), level(g), level(b)
+	cube := 16 + 36*cr + 6*cg + cb
+
+	gray := ((r+g+b)/3 - 8) / 10
+	if gray < 0 {
+		gray = 0
+	}
+	if gray > 23 {
+		gray = 23
+	}
+	v := 8 + gray*10
+
+	if colorDistance(r, g, b, v, v, v) < colorDistance(r, g, b, cubeLevels[cr], cubeLevels[cg], cubeLevels[cb]) {
+		return 232 + gray
+	}
+	return cube
+}
+
+// colorDistance returns the squared distance between two colors.
+func colorDistance(r1, g1, b1, r2, g2, b2 int) int {
+	dr, dg, db := r1-r2, g1-g2, b1-b2
+	return dr*dr + dg*dg + db*db
+}
+
+// ansi16 returns the SGR parameter of the basic color that best matches a
+// 24-bit color. The hue comes from the channels within 70% of the brightest
+// one, and light colors use the bright variant.
+func ansi16(r, g, b int, bg bool) string {
+	hi := r
+	if g > hi {
+		hi = g
+	}
+	if b > hi {
+		hi = b
+	}
+
+	base := 30
+	if bg {
+		base = 40
+	}
+	value := (hi*100/255 + 25) / 50 // 0, 1 or 2
+	if value == 0 && bg {
+		// A near-black tint keeps the terminal's own background.
+		return "49"
+	}
+	if value == 0 {
+		return strconv.Itoa(base)
+	}
+	bit := func(c int) int {
+		if c*10 >= hi*7 {
+			return 1
+		}
+		return 0
+	}
+	code := base + (bit(b)<<2 | bit(g)<<1 | bit(r))
+	if value == 2 {
+		code += 60
+	}
+	return strconv.Itoa(code)
+}
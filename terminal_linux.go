@@ -0,0 +1,25 @@
+//go:build linux
+
+package godump
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isTerminalFile reports whether f is a terminal, by asking the kernel for
+// its terminal attributes.
+func isTerminalFile(f *os.File) bool {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return false
+	}
+	tty := false
+	_ = conn.Control(func(fd uintptr) {
+		var termios syscall.Termios
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&termios)))
+		tty = errno == 0
+	})
+	return tty
+}
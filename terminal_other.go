@@ -0,0 +1,12 @@
+//go:build !linux
+
+package godump
+
+import "os"
+
+// isTerminalFile reports whether f is a character device, such as a
+// terminal or console.
+func isTerminalFile(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
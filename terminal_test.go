@@ -0,0 +1,127 @@
+package godump
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"testing"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+// unsetColorEnv unsets the variables that control colors for the test.
+func unsetColorEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{"NO_COLOR", "FORCE_COLOR", "TERM", "COLORTERM"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+func TestDetectColorDepthEnvironment(t *testing.T) {
+	cases := []struct {
+		name string
+		env  map[string]string
+		want colorDepth
+	}{
+		{"not a terminal", map[string]string{"TERM": "xterm-256color"}, colorDepthNone},
+		{"force basic", map[string]string{"FORCE_COLOR": "1", "TERM": "xterm"}, colorDepth16},
+		{"force keeps term depth", map[string]string{"FORCE_COLOR": "true", "TERM": "xterm-256color"}, colorDepth256},
+		{"force empty", map[string]string{"FORCE_COLOR": ""}, colorDepth16},
+		{"force 256", map[string]string{"FORCE_COLOR": "2", "TERM": "dumb"}, colorDepth256},
+		{"force truecolor", map[string]string{"FORCE_COLOR": "3"}, colorDepthTrue},
+		{"force colorterm", map[string]string{"FORCE_COLOR": "1", "COLORTERM": "truecolor"}, colorDepthTrue},
+		{"force off", map[string]string{"FORCE_COLOR": "0", "COLORTERM": "truecolor"}, colorDepthNone},
+		{"no color wins", map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "3"}, colorDepthNone},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			unsetColorEnv(t)
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			assert.Equal(t, tc.want, detectColorDepth(&bytes.Buffer{}))
+		})
+	}
+}
+
+func TestTermColorDepth(t *testing.T) {
+	assert.Equal(t, colorDepthTrue, termColorDepth("xterm-256color", "truecolor"))
+	assert.Equal(t, colorDepthTrue, termColorDepth("screen", "24bit"))
+	assert.Equal(t, colorDepthTrue, termColorDepth("xterm-direct", ""))
+	assert.Equal(t, colorDepth256, termColorDepth("tmux-256color", ""))
+	assert.Equal(t, colorDepth16, termColorDepth("xterm", ""))
+	assert.Equal(t, colorDepth16, termColorDepth("", ""))
+	assert.Equal(t, colorDepthNone, termColorDepth("dumb", ""))
+}
+
+func TestIsTerminal(t *testing.T) {
+	assert.False(t, isTerminal(&bytes.Buffer{}))
+	assert.False(t, isTerminal((*os.File)(nil)))
+
+	f, err := os.Create(t.TempDir() + "/out")
+	assert.NoError(t, err)
+	defer f.Close()
+	assert.False(t, isTerminal(f))
+
+	// /dev/null is a character device but not a terminal.
+	if runtime.GOOS == "linux" {
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		assert.NoError(t, err)
+		defer devNull.Close()
+		assert.False(t, isTerminal(devNull))
+	}
+}
+
+func TestDownsampleANSI(t *testing.T) {
+	esc := string(ansiEscape)
+
+	// 256-color codes pass unchanged to 256-color terminals.
+	assert.Equal(t, colorLime, downsampleANSI(colorLime, colorDepth256))
+	assert.Equal(t, colorRedBg, downsampleANSI(colorRedBg, colorDepthTrue))
+
+	// 24-bit colors map into the color cube or the gray ramp.
+	assert.Equal(t, esc+"[48;5;233m", downsampleANSI(colorRedBg, colorDepth256))
+	assert.Equal(t, esc+"[38;5;196m", downsampleANSI(esc+"[38;2;255;0;0m", colorDepth256))
+	assert.Equal(t, esc+"[1;38;5;244m", downsampleANSI(esc+"[1;38;2;128;128;128m", colorDepth256))
+
+	// 16-color terminals get the basic color of the same hue.
+	assert.Equal(t, esc+"[1;92m", downsampleANSI(colorLime, colorDepth16))
+	assert.Equal(t, esc+"[96m", downsampleANSI(colorCyan, colorDepth16))
+	assert.Equal(t, esc+"[37m", downsampleANSI(colorRef, colorDepth16))
+	assert.Equal(t, esc+"[95m", downsampleANSI(colorMeta, colorDepth16))
+	assert.Equal(t, esc+"[49m", downsampleANSI(colorRedBg, colorDepth16))
+	assert.Equal(t, esc+"[30m", downsampleANSI(esc+"[38;2;20;20;20m", colorDepth16))
+	assert.Equal(t, esc+"[107m", downsampleANSI(LightTheme().Styles[RoleDiffDel].ANSI, colorDepth16))
+	assert.Equal(t, esc+"[91m", downsampleANSI(esc+"[38;5;196m", colorDepth16))
+
+	// Basic codes and malformed sequences are left alone.
+	assert.Equal(t, colorYellow, downsampleANSI(colorYellow, colorDepth16))
+	assert.Equal(t, esc+"[38;5m", downsampleANSI(esc+"[38;5m", colorDepth16))
+	assert.Equal(t, esc+"[38;5;x;1m", downsampleANSI(esc+"[38;5;x;1m", colorDepth16))
+	assert.Equal(t, "plain", downsampleANSI("plain", colorDepth16))
+}
+
+func TestColorizerFollowsWriterDepth(t *testing.T) {
+	unsetColorEnv(t)
+	t.Setenv("FORCE_COLOR", "1")
+
+	d := NewDumper(WithoutHeader(), WithWriter(&bytes.Buffer{}))
+	out := d.DumpStr("hi")
+	assert.Contains(t, out, string(ansiEscape)+"[1;92mhi")
+	assert.NotContains(t, out, ";5;")
+
+	diff := NewDumper(WithoutHeader()).DiffStr(1, 2)
+	assert.Contains(t, diff, string(ansiEscape)+"[49m")
+	assert.NotContains(t, diff, ";2;")
+
+	// Without a terminal or FORCE_COLOR, dumps and diffs are plain text.
+	os.Unsetenv("FORCE_COLOR")
+	var buf bytes.Buffer
+	d = NewDumper(WithoutHeader(), WithWriter(&buf))
+	d.Dump(map[string]int{"a": 1})
+	d.Diff(1, 2)
+	assert.NotContains(t, buf.String(), string(ansiEscape))
+	assert.Contains(t, buf.String(), "a => 1 #int")
+	assert.Contains(t, buf.String(), "- 1 #int")
+}
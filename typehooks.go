@@ -0,0 +1,128 @@
+package godump
+
+import "reflect"
+
+// TypeFormatter renders a value of a registered type as a single scalar string.
+type TypeFormatter func(v reflect.Value) string
+
+// TypeExpander returns the ordered, labeled children to print for a value of a registered type.
+type TypeExpander func(v reflect.Value) []Child
+
+// Child is a labeled child value produced by a [TypeExpander].
+type Child struct {
+	Label string
+	Value any
+}
+
+// typeHook is a formatter or expander registered for a concrete or interface type.
+type typeHook struct {
+	typ    reflect.Type
+	format TypeFormatter
+	expand TypeExpander
+}
+
+// WithTypeFormatter renders values of type t with fn instead of the default output.
+// When t is an interface type, every implementer of t is formatted with fn.
+// @group Options
+//
+// Example: format a type you don't own
+//
+//	// Default: none
+//	type Cents int64
+//	d := godump.NewDumper(godump.WithTypeFormatter(reflect.TypeOf(Cents(0)), func(v reflect.Value) string {
+//		return fmt.Sprintf("$%d.%02d", v.Int()/100, v.Int()%100)
+//	}))
+//	d.Dump(Cents(1999))
+//	// $19.99 #main.Cents
+func WithTypeFormatter(t reflect.Type, fn TypeFormatter) Option {
+	return func(d *Dumper) *Dumper {
+		if t != nil && fn != nil {
+			d.typeHooks = append(d.typeHooks, typeHook{typ: t, format: fn})
+		}
+		return d
+	}
+}
+
+// WithTypeExpander renders values of type t as the labeled children returned by fn.
+// When t is an interface type, every implementer of t is expanded with fn.
+// @group Options
+//
+// Example: expand a linked list
+//
+//	// Default: none
+//	type Node struct {
+//		Val  int
+//		Next *Node
+//	}
+//	d := godump.NewDumper(godump.WithTypeExpander(reflect.TypeOf(Node{}), func(v reflect.Value) []godump.Child {
+//		var out []godump.Child
+//		for n := v.Addr().Interface().(*Node); n != nil; n = n.Next {
+//			out = append(out, godump.Child{Label: fmt.Sprint(len(out)), Value: n.Val})
+//		}
+//		return out
+//	}))
+//	d.Dump(Node{Val: 1, Next: &Node{Val: 2}})
+//	// #main.Node {
+//	//   0 => 1 #int
+//	//   1 => 2 #int
+//	// }
+func WithTypeExpander(t reflect.Type, fn TypeExpander) Option {
+	return func(d *Dumper) *Dumper {
+		if t != nil && fn != nil {
+			d.typeHooks = append(d.typeHooks, typeHook{typ: t, expand: fn})
+		}
+		return d
+	}
+}
+
+// findTypeHook returns the hook registered for t, preferring exact matches
+// over interface matches and earlier registrations over later ones.
+func (d *Dumper) findTypeHook(t reflect.Type) (typeHook, bool) {
+	for _, h := range d.typeHooks {
+		if h.typ == t {
+			return h, true
+		}
+	}
+	for _, h := range d.typeHooks {
+		if h.typ.Kind() == reflect.Interface && t.Implements(h.typ) {
+			return h, true
+		}
+	}
+	return typeHook{}, false
+}
+
+// inspectTypeHook builds the node for v through a registered hook, following
+// pointers until a hook matches. It returns nil when no hook applies.
+func (d *Dumper) inspectTypeHook(v reflect.Value, depth int, state *dumpState) *Node {
+	h, hv, typeStr, ok := d.hookFor(v)
+	if !ok {
+		return nil
+	}
+	if h.format != nil {
+		return &Node{Kind: NodeText, Type: typeStr, Value: h.format(hv)}
+	}
+	n := &Node{Kind: NodeExpanded, Type: typeStr}
+	d.inspectChildren(n, h.expand(hv), depth, state)
+	return n
+}
+
+// hookFor follows pointers from v until a registered hook matches. It returns
+// the hook, the value to pass to it and the type name to print.
+func (d *Dumper) hookFor(v reflect.Value) (typeHook, reflect.Value, string, bool) {
+	// Interface values are matched by their dynamic type once unwrapped.
+	if len(d.typeHooks) == 0 || v.Kind() == reflect.Interface {
+		return typeHook{}, reflect.Value{}, "", false
+	}
+
+	ptrPrefix := ""
+	for {
+		if plan := d.plan(v.Type()); plan.hasHook {
+			return plan.hook, forceExported(v), ptrPrefix + plan.typeString, true
+		}
+		if v.Kind() != reflect.Ptr || v.IsNil() {
+			return typeHook{}, reflect.Value{}, "", false
+		}
+		ptrPrefix += "*"
+		v = v.Elem()
+	}
+}
@@ -0,0 +1,112 @@
+package godump
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+type hookCents int64
+
+type hookShape interface {
+	Area() float64
+}
+
+type hookSquare struct{ Side float64 }
+
+func (s hookSquare) Area() float64 { return s.Side * s.Side }
+
+type hookCircle struct{ R float64 }
+
+func (c *hookCircle) Area() float64 { return 3 * c.R * c.R }
+
+type hookList struct {
+	Val  int
+	Next *hookList
+}
+
+func formatCents(v reflect.Value) string {
+	return fmt.Sprintf("$%d.%02d", v.Int()/100, v.Int()%100)
+}
+
+func expandList(v reflect.Value) []Child {
+	var out []Child
+	for n := v.Addr().Interface().(*hookList); n != nil; n = n.Next {
+		out = append(out, Child{Label: fmt.Sprintf("[%d]", len(out)), Value: n.Val})
+	}
+	return out
+}
+
+func TestTypeFormatter(t *testing.T) {
+	d := newDumperT(t, WithoutHeader(), WithTypeFormatter(reflect.TypeOf(hookCents(0)), formatCents))
+
+	out := d.DumpStr(hookCents(1999))
+	assert.Equal(t, "$19.99 #godump.hookCents\n", out)
+
+	type Order struct {
+		Total hookCents
+		Tip   *hookCents
+	}
+	tip := hookCents(250)
+	out = d.DumpStr(Order{Total: 500, Tip: &tip})
+	assert.Contains(t, out, "+Total => $5.00 #godump.hookCents")
+	assert.Contains(t, out, "+Tip   => $2.50 #*godump.hookCents")
+
+	out = d.DumpStr(Order{Total: 1})
+	assert.Contains(t, out, "+Tip   => *godump.hookCents(nil)")
+}
+
+func TestTypeFormatterOverridesStringer(t *testing.T) {
+	d := newDumperT(t, WithoutHeader(), WithTypeFormatter(reflect.TypeOf(time.Time{}), func(v reflect.Value) string {
+		return v.Interface().(time.Time).Format("2006-01-02")
+	}))
+
+	out := d.DumpStr(time.Date(2024, time.June, 1, 10, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2024-06-01 #time.Time\n", out)
+}
+
+func TestTypeFormatterMatchesInterfaces(t *testing.T) {
+	shapeType := reflect.TypeOf((*hookShape)(nil)).Elem()
+	d := newDumperT(t, WithoutHeader(), WithTypeFormatter(shapeType, func(v reflect.Value) string {
+		return fmt.Sprintf("area=%g", v.Interface().(hookShape).Area())
+	}))
+
+	out := d.DumpStr([]hookShape{hookSquare{Side: 2}, &hookCircle{R: 1}})
+	assert.Contains(t, out, "0 => area=4 #godump.hookSquare")
+	assert.Contains(t, out, "1 => area=3 #*godump.hookCircle")
+}
+
+func TestTypeHookExactMatchWinsOverInterface(t *testing.T) {
+	shapeType := reflect.TypeOf((*hookShape)(nil)).Elem()
+	d := newDumperT(t, WithoutHeader(),
+		WithTypeFormatter(shapeType, func(v reflect.Value) string { return "shape" }),
+		WithTypeFormatter(reflect.TypeOf(hookSquare{}), func(v reflect.Value) string { return "square" }),
+	)
+
+	assert.Contains(t, d.DumpStr(hookSquare{}), "square #godump.hookSquare")
+}
+
+func TestTypeExpander(t *testing.T) {
+	d := newDumperT(t, WithoutHeader(), WithTypeExpander(reflect.TypeOf(hookList{}), expandList))
+
+	out := d.DumpStr(&hookList{Val: 1, Next: &hookList{Val: 2, Next: &hookList{Val: 3}}})
+	assert.Equal(t, "#*godump.hookList {\n  [0] => 1 #int\n  [1] => 2 #int\n  [2] => 3 #int\n}\n", out)
+
+	d = newDumperT(t, WithoutHeader(), WithMaxItems(1), WithTypeExpander(reflect.TypeOf(hookList{}), expandList))
+	out = d.DumpStr(hookList{Val: 1, Next: &hookList{Val: 2}})
+	assert.Contains(t, out, "[0] => 1 #int")
+	assert.NotContains(t, out, "[1]")
+	assert.Contains(t, out, "... (truncated)")
+}
+
+func TestTypeHooksIgnoreNilRegistrations(t *testing.T) {
+	d := NewDumper(
+		WithTypeFormatter(nil, formatCents),
+		WithTypeFormatter(reflect.TypeOf(hookCents(0)), nil),
+		WithTypeExpander(reflect.TypeOf(hookList{}), nil),
+	)
+	assert.Equal(t, 0, len(d.typeHooks))
+}
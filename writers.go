@@ -0,0 +1,143 @@
+package godump
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// writerLock serializes output to one destination writer.
+type writerLock struct {
+	mu   sync.Mutex
+	refs int
+	// owner is the id of the goroutine holding mu, or 0. It is guarded by
+	// writerLocksMu.
+	owner uint64
+}
+
+var (
+	writerLocksMu sync.Mutex
+	// writerLocks holds a lock for every writer currently being written to.
+	// Entries are removed once unused so short-lived writers do not leak.
+	writerLocks = map[any]*writerLock{}
+)
+
+// sharedWriterKey is used for nil writers.
+type sharedWriterKey struct{}
+
+// writerTypeKey identifies writers that are not pointers by their type and,
+// for maps, the map they refer to.
+type writerTypeKey struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// lockWriter blocks until w is free and returns the function that releases
+// it. All Dumpers share the locks, so concurrent dumps to the same writer are
+// written one after another rather than interleaved. A goroutine that already
+// holds the lock, such as a String method dumping while its value is being
+// dumped, writes without waiting.
+func lockWriter(w io.Writer) (unlock func()) {
+	key := writerKey(w)
+	id, _ := goroutineID()
+
+	writerLocksMu.Lock()
+	l := writerLocks[key]
+	if l == nil {
+		l = &writerLock{}
+		writerLocks[key] = l
+	}
+	if id != 0 && l.owner == id {
+		writerLocksMu.Unlock()
+		return func() {}
+	}
+	l.refs++
+	writerLocksMu.Unlock()
+
+	l.mu.Lock()
+	writerLocksMu.Lock()
+	l.owner = id
+	writerLocksMu.Unlock()
+	return func() {
+		writerLocksMu.Lock()
+		l.owner = 0
+		writerLocksMu.Unlock()
+		l.mu.Unlock()
+
+		writerLocksMu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(writerLocks, key)
+		}
+		writerLocksMu.Unlock()
+	}
+}
+
+// writerKey identifies w by pointer when possible. Writers of other kinds may
+// not be usable as map keys, so writers of the same such type share a lock.
+func writerKey(w io.Writer) any {
+	if w == nil {
+		return sharedWriterKey{}
+	}
+	v := reflect.ValueOf(w)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		return w
+	case reflect.Map:
+		return writerTypeKey{typ: v.Type(), ptr: v.Pointer()}
+	default:
+		return writerTypeKey{typ: v.Type()}
+	}
+}
+
+// writeLocked writes a complete rendering to w while holding its lock.
+func writeLocked(w io.Writer, s string) {
+	defer lockWriter(w)()
+	io.WriteString(w, s)
+}
+
+// WithGoroutineID adds the id of the calling goroutine to the dump and diff
+// headers, so output from concurrent workers can be told apart.
+// @group Options
+//
+// Example: tag dumps with the goroutine id
+//
+//	// Default: disabled
+//	d := godump.NewDumper(godump.WithGoroutineID())
+//	d.Dump("job started")
+//	// <#dump // main.go:12 [goroutine 1]
+//	// "job started" #string
+func WithGoroutineID() Option {
+	return func(d *Dumper) *Dumper {
+		d.showGoroutineID = true
+		return d
+	}
+}
+
+// goroutineID returns the id of the calling goroutine, parsed from the
+// "goroutine N [status]:" line that starts its stack trace.
+func goroutineID() (uint64, bool) {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseUint(string(b), 10, 64)
+	return id, err == nil
+}
+
+// goroutineTag returns the " [goroutine N]" header suffix when enabled.
+func (d *Dumper) goroutineTag() string {
+	if !d.showGoroutineID {
+		return ""
+	}
+	id, ok := goroutineID()
+	if !ok {
+		return ""
+	}
+	return " [goroutine " + strconv.FormatUint(id, 10) + "]"
+}
@@ -0,0 +1,149 @@
+package godump
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	assert "github.com/goforj/godump/internal/testassert"
+)
+
+// yieldingWriter yields after every write to provoke interleaving.
+type yieldingWriter struct {
+	mu sync.Mutex
+	sb strings.Builder
+}
+
+func (w *yieldingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	w.sb.Write(p)
+	w.mu.Unlock()
+	runtime.Gosched()
+	return len(p), nil
+}
+
+func TestConcurrentDumpsAreNotInterleaved(t *testing.T) {
+	items := make([]int, 600)
+	var w yieldingWriter
+	d := NewDumper(WithWriter(&w), WithoutColor(), WithoutHeader(), WithMaxItems(len(items)))
+	want := d.DumpStr(items)
+	assert.True(t, len(want) > 4096) // forces several writes per dump
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				d.Dump(items)
+				return
+			}
+			// A different Dumper on the same writer is serialized too.
+			NewDumper(WithWriter(&w), WithoutColor(), WithoutHeader(), WithMaxItems(len(items))).Dump(items)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, strings.Repeat(want, workers), w.sb.String())
+
+	writerLocksMu.Lock()
+	defer writerLocksMu.Unlock()
+	assert.Equal(t, 0, len(writerLocks))
+}
+
+func TestConcurrentDiffAndOtherOutputs(t *testing.T) {
+	var w yieldingWriter
+	d := NewDumper(WithWriter(&w), WithoutHeader())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			d.Diff(map[string]int{"a": 1}, map[string]int{"a": 2})
+		}()
+		go func() {
+			defer wg.Done()
+			d.DumpJSON(map[string]int{"a": 1})
+		}()
+		go func() {
+			defer wg.Done()
+			d.DumpGo([]int{1})
+		}()
+	}
+	wg.Wait()
+
+	out := stripANSI(w.sb.String())
+	assert.Equal(t, 4, strings.Count(out, "-    a => 1 #int\n"))
+	assert.Equal(t, 4, strings.Count(out, "{\n  \"a\": 1\n}\n"))
+	assert.Equal(t, 4, strings.Count(out, "[]int{\n\t1,\n}\n"))
+}
+
+func TestWriterKey(t *testing.T) {
+	var sb strings.Builder
+	assert.Equal(t, any(&sb), writerKey(&sb))
+	assert.Equal(t, any(sharedWriterKey{}), writerKey(nil))
+	assert.Equal(t, any(writerTypeKey{typ: reflect.TypeOf(funcWriter(nil))}), writerKey(funcWriter(nil)))
+	assert.Equal(t, any(writerTypeKey{typ: reflect.TypeOf(mapWriter(nil))}), writerKey(mapWriter(nil)))
+
+	// Writers of different types do not share a lock.
+	assert.True(t, writerKey(funcWriter(nil)) != writerKey(valueWriter{}))
+}
+
+type mapWriter map[int]int
+
+func (mapWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+type valueWriter struct{}
+
+func (valueWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+// dumpingStringer dumps to w from its String method.
+type dumpingStringer struct{ w *strings.Builder }
+
+func (s dumpingStringer) String() string {
+	NewDumper(WithWriter(s.w), WithoutColor(), WithoutHeader()).Dump("inner")
+	return "outer"
+}
+
+func TestDumpFromStringerToSameWriter(t *testing.T) {
+	var sb strings.Builder
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewDumper(WithWriter(&sb), WithoutColor(), WithoutHeader()).Dump(dumpingStringer{w: &sb})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dump from a String method deadlocked")
+	}
+	assert.Contains(t, sb.String(), `"inner" #string`)
+	assert.Contains(t, sb.String(), "outer #godump.dumpingStringer")
+}
+
+type funcWriter func([]byte) (int, error)
+
+func (f funcWriter) Write(p []byte) (int, error) { return f(p) }
+
+func TestGoroutineIDHeader(t *testing.T) {
+	id, ok := goroutineID()
+	assert.True(t, ok)
+	assert.True(t, id > 0)
+
+	out := NewDumper(WithoutColor(), WithGoroutineID()).DumpStr(1)
+	assert.Contains(t, out, fmt.Sprintf(" [goroutine %d]\n", id))
+
+	out = NewDumper(WithoutColor()).DumpStr(1)
+	assert.NotContains(t, out, "goroutine")
+
+	out = NewDumper(WithoutColor(), WithGoroutineID()).DiffStr(1, 2)
+	assert.Contains(t, out, "<#diff // writers_test.go:")
+	assert.Contains(t, out, fmt.Sprintf(" [goroutine %d]\n", id))
+}